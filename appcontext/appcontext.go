@@ -17,9 +17,12 @@
 package appcontext
 
 import (
+	"context"
+	"errors"
 	"github.com/xfali/fig"
 	"github.com/xfali/neve-core/bean"
 	"github.com/xfali/neve-core/processor"
+	"reflect"
 )
 
 type ApplicationContext interface {
@@ -41,12 +44,14 @@ type ApplicationContext interface {
 	GetBean(name string) (interface{}, bool)
 
 	// 从容器注入对象，如果容器中包含该对象，返回true否则返回false
+	// o为指向接收对象的变量的指针，如：var a A; ctx.GetBeanByType(&a)
 	GetBeanByType(o interface{}) bool
 
 	// 增加对象处理器，用于对对象进行分类和处理
 	AddProcessor(processor.Processor) error
 
 	// 启动应用
+	// 注入之前静态检测构造方法之间的循环依赖，存在时返回*CircularDependencyError
 	Start() error
 
 	// 关闭，用于资源回收
@@ -57,6 +62,144 @@ type ApplicationContext interface {
 	ApplicationEventHandler
 }
 
+// 以下为ApplicationContext的可选接口，默认的ApplicationContext均已实现。
+// 自定义的ApplicationContext（如OptSetApplicationContext）可以按需实现，
+// 应使用ParentOf、RegisterBeanDynamic、ReplaceBean、RemoveBean、ResolveBean、ResolveBeans、NewScope、DependencyGraphOf、Verify函数调用
+
+// 支持父ApplicationContext的ApplicationContext
+type HierarchicalApplicationContext interface {
+	ApplicationContext
+
+	// 获得父ApplicationContext，不存在时返回nil
+	GetParent() ApplicationContext
+}
+
+// 支持运行时动态注册、替换及移除对象的ApplicationContext
+type DynamicApplicationContext interface {
+	ApplicationContext
+
+	// 动态注册对象，name为空时使用默认名称
+	// 启动完成后容器被冻结（neve.application.freeze，默认true），RegisterBean、RegisterBeanByName返回bean.ErrContainerFrozen，
+	// 需要通过该方法注册：对象立即完成注入及初始化（延迟初始化的对象在获取时初始化），注册条件立即判断
+	// 启动之前调用时与RegisterBeanByName一致
+	RegisterBeanDynamic(name string, o interface{}, opts ...bean.RegisterOpt) error
+
+	// 使用新的对象替换指定名称的对象（运行时热替换）
+	// 启动之后替换时：新对象完成注入及初始化，依赖原对象的对象重新注入，原对象被销毁，并发布BeanReplacedEvent事件
+	// 注意：通过构造方法创建的依赖方无法重新注入
+	ReplaceBean(name string, o interface{}, opts ...bean.RegisterOpt) error
+
+	// 移除指定名称的对象
+	// 启动之后移除时：对象被销毁，并发布BeanRemovedEvent事件
+	RemoveBean(name string) error
+}
+
+// 按照注入时的规则查找对象定义的ApplicationContext，泛型方法Get、GetNamed、GetAll依赖该接口
+type BeanResolver interface {
+	ApplicationContext
+
+	// 按照注入时的规则根据名称及类型查找对象定义，name为空时按类型自动匹配，详情查看injector.Resolve
+	// 推荐使用泛型方法Get、GetNamed
+	ResolveBean(name string, t reflect.Type) (bean.Definition, error)
+
+	// 查找所有可以赋值给类型t的对象定义
+	// 推荐使用泛型方法GetAll
+	ResolveBeans(t reflect.Type) []bean.Definition
+}
+
+// 支持Bean作用域的ApplicationContext
+type ScopedApplicationContext interface {
+	ApplicationContext
+
+	// 创建绑定ctx生命周期的Bean作用域，作用域为bean.Context的对象在作用域内缓存，并在作用域结束时销毁
+	NewScope(ctx context.Context) BeanScope
+}
+
+// 支持依赖图及依赖检查的ApplicationContext
+type InspectableApplicationContext interface {
+	ApplicationContext
+
+	// 获得bean依赖图，包含所有注册的bean以及注入时记录的依赖关系（字段及方法参数）
+	// 可以导出为Graphviz DOT及JSON格式
+	DependencyGraph() *DependencyGraph
+
+	// 检查依赖：静态解析所有inject标记的字段、InjectFunction注册的注入方法及构造方法的参数，不会创建对象或者调用生命周期方法，也不会修改容器（不满足注册条件的bean仅在检查时忽略）
+	// 存在无法注入、无法确定的依赖或者循环依赖时返回检查报告及错误，应在Start之前调用
+	Verify() (*VerifyReport, error)
+}
+
+// 获得父ApplicationContext，未实现HierarchicalApplicationContext或者不存在时返回nil
+func ParentOf(ctx ApplicationContext) ApplicationContext {
+	if v, ok := ctx.(HierarchicalApplicationContext); ok {
+		return v.GetParent()
+	}
+	return nil
+}
+
+// 动态注册对象，未实现DynamicApplicationContext时返回错误
+func RegisterBeanDynamic(ctx ApplicationContext, name string, o interface{}, opts ...bean.RegisterOpt) error {
+	if v, ok := ctx.(DynamicApplicationContext); ok {
+		return v.RegisterBeanDynamic(name, o, opts...)
+	}
+	return errors.New("ApplicationContext not support dynamic registration. ")
+}
+
+// 替换指定名称的对象，未实现DynamicApplicationContext时返回错误
+func ReplaceBean(ctx ApplicationContext, name string, o interface{}, opts ...bean.RegisterOpt) error {
+	if v, ok := ctx.(DynamicApplicationContext); ok {
+		return v.ReplaceBean(name, o, opts...)
+	}
+	return errors.New("ApplicationContext not support replace. ")
+}
+
+// 移除指定名称的对象，未实现DynamicApplicationContext时返回错误
+func RemoveBean(ctx ApplicationContext, name string) error {
+	if v, ok := ctx.(DynamicApplicationContext); ok {
+		return v.RemoveBean(name)
+	}
+	return errors.New("ApplicationContext not support remove. ")
+}
+
+// 按照注入时的规则查找对象定义，未实现BeanResolver时返回错误
+func ResolveBean(ctx ApplicationContext, name string, t reflect.Type) (bean.Definition, error) {
+	if v, ok := ctx.(BeanResolver); ok {
+		return v.ResolveBean(name, t)
+	}
+	return nil, errors.New("ApplicationContext not support resolve. ")
+}
+
+// 查找所有可以赋值给类型t的对象定义，未实现BeanResolver时返回nil
+func ResolveBeans(ctx ApplicationContext, t reflect.Type) []bean.Definition {
+	if v, ok := ctx.(BeanResolver); ok {
+		return v.ResolveBeans(t)
+	}
+	return nil
+}
+
+// 创建绑定c生命周期的Bean作用域，未实现ScopedApplicationContext时返回错误
+func NewScope(ctx ApplicationContext, c context.Context) (BeanScope, error) {
+	if v, ok := ctx.(ScopedApplicationContext); ok {
+		return v.NewScope(c), nil
+	}
+	return nil, errors.New("ApplicationContext not support bean scope. ")
+}
+
+// 获得bean依赖图，未实现InspectableApplicationContext时返回nil
+func DependencyGraphOf(ctx ApplicationContext) *DependencyGraph {
+	if v, ok := ctx.(InspectableApplicationContext); ok {
+		return v.DependencyGraph()
+	}
+	return nil
+}
+
+// 检查依赖，未实现InspectableApplicationContext时返回错误
+func Verify(ctx ApplicationContext) (*VerifyReport, error) {
+	if v, ok := ctx.(InspectableApplicationContext); ok {
+		return v.Verify()
+	}
+	return nil, errors.New("ApplicationContext not support verify. ")
+}
+
 type ApplicationContextAware interface {
 	// 装配ApplicationContext
 	// 在bean未被注入和初始化之前调用
@@ -19,6 +19,7 @@ package appcontext
 
 import (
 	"context"
+	"github.com/xfali/neve-core/bean"
 	"time"
 )
 
@@ -96,6 +97,67 @@ func NewContextClosedEvent(appCtx ApplicationContext) *ContextClosedEvent {
 	return ret
 }
 
+// 运行时bean被替换后触发，依赖该bean的对象已重新注入，原bean已销毁
+type BeanReplacedEvent struct {
+	ApplicationContextEvent
+	name   string
+	oldDef bean.Definition
+	newDef bean.Definition
+}
+
+func NewBeanReplacedEvent(appCtx ApplicationContext, name string, oldDef, newDef bean.Definition) *BeanReplacedEvent {
+	ret := &BeanReplacedEvent{
+		name:   name,
+		oldDef: oldDef,
+		newDef: newDef,
+	}
+	ret.ResetOccurredTime()
+	ret.appCtx = appCtx
+	return ret
+}
+
+// 获得被替换的bean名称
+func (e *BeanReplacedEvent) GetBeanName() string {
+	return e.name
+}
+
+// 获得原bean的对象定义
+func (e *BeanReplacedEvent) GetOldDefinition() bean.Definition {
+	return e.oldDef
+}
+
+// 获得新bean的对象定义
+func (e *BeanReplacedEvent) GetNewDefinition() bean.Definition {
+	return e.newDef
+}
+
+// 运行时bean被移除后触发，bean已销毁
+type BeanRemovedEvent struct {
+	ApplicationContextEvent
+	name string
+	def  bean.Definition
+}
+
+func NewBeanRemovedEvent(appCtx ApplicationContext, name string, def bean.Definition) *BeanRemovedEvent {
+	ret := &BeanRemovedEvent{
+		name: name,
+		def:  def,
+	}
+	ret.ResetOccurredTime()
+	ret.appCtx = appCtx
+	return ret
+}
+
+// 获得被移除的bean名称
+func (e *BeanRemovedEvent) GetBeanName() string {
+	return e.name
+}
+
+// 获得被移除的bean的对象定义
+func (e *BeanRemovedEvent) GetDefinition() bean.Definition {
+	return e.def
+}
+
 // GetEventContext 从事件中提取事件context
 // 参数 e: 事件
 // 参数 defaultCtx: 默认context，如果e事件实现了EventContextHolder则返回事件的context，否则返回defaultCtx
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"github.com/xfali/neve-core/bean"
+	errors2 "github.com/xfali/neve-core/errors"
+)
+
+// 配置了注册条件的bean，在启动时判断条件
+type conditionalBean struct {
+	name       string
+	o          interface{}
+	container  bean.Container
+	lazy       bool
+	conditions []bean.Condition
+}
+
+func (ctx *defaultApplicationContext) addConditionalBean(name string, o interface{}, c bean.Container, opts *registerOptions) {
+	ctx.conditionalLock.Lock()
+	defer ctx.conditionalLock.Unlock()
+
+	ctx.conditionalBeans = append(ctx.conditionalBeans, &conditionalBean{
+		name:       name,
+		o:          o,
+		container:  c,
+		lazy:       opts.lazy,
+		conditions: opts.conditions,
+	})
+}
+
+// 按注册顺序判断bean的注册条件，不满足条件的bean从容器中移除
+// 注意：判断时尚未判断条件的bean仍在容器中
+func (ctx *defaultApplicationContext) checkConditions() error {
+	ctx.conditionalLock.Lock()
+	beans := ctx.conditionalBeans
+	ctx.conditionalBeans = nil
+	ctx.conditionalLock.Unlock()
+
+	var errs errors2.Errors
+	for _, b := range beans {
+		d, ok := ctx.container.GetDefinition(b.name)
+		if !ok {
+			continue
+		}
+		if ctx.matchConditions(ctx.container, b, d) {
+			err := ctx.postRegister(b.o, b.container, b.lazy)
+			if err != nil {
+				errs.AddError(err)
+			}
+		} else {
+			bean.Remove(ctx.container, b.name)
+			ctx.graph.remove(d)
+		}
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+// 立即判断注册条件，未配置条件时返回true
+func (ctx *defaultApplicationContext) matchRegisterConditions(name string, opts *registerOptions, d bean.Definition) bool {
+	if len(opts.conditions) == 0 {
+		return true
+	}
+	return ctx.matchConditions(ctx.container, &conditionalBean{
+		name:       name,
+		conditions: opts.conditions,
+	}, d)
+}
+
+func (ctx *defaultApplicationContext) matchConditions(container bean.Container, b *conditionalBean, d bean.Definition) bool {
+	for _, c := range b.conditions {
+		if !c.Matches(ctx.config, container, d) {
+			ctx.logger.Infof("Bean [%s] condition %s not matched, skip registration\n", b.name, c.String())
+			return false
+		}
+		ctx.logger.Infof("Bean [%s] condition %s matched\n", b.name, c.String())
+	}
+	return true
+}
+
+// 按注册顺序判断bean的注册条件但不修改容器，返回隐藏了不满足条件的bean的容器视图，用于依赖检查
+func (ctx *defaultApplicationContext) previewConditions() *conditionView {
+	ctx.conditionalLock.Lock()
+	beans := make([]*conditionalBean, len(ctx.conditionalBeans))
+	copy(beans, ctx.conditionalBeans)
+	ctx.conditionalLock.Unlock()
+
+	view := &conditionView{
+		Container: ctx.container,
+		removed:   map[bean.Definition]struct{}{},
+	}
+	for _, b := range beans {
+		d, ok := view.GetDefinition(b.name)
+		if !ok {
+			continue
+		}
+		if !ctx.matchConditions(view, b, d) {
+			view.removed[d] = struct{}{}
+		}
+	}
+	return view
+}
+
+// 隐藏了部分对象定义的容器视图
+type conditionView struct {
+	bean.Container
+	removed map[bean.Definition]struct{}
+}
+
+func (v *conditionView) contains(d bean.Definition) bool {
+	_, ok := v.removed[d]
+	return !ok
+}
+
+func (v *conditionView) Get(name string) (interface{}, bool) {
+	d, ok := v.GetDefinition(name)
+	if !ok {
+		return nil, false
+	}
+	return d.Value().Interface(), true
+}
+
+func (v *conditionView) GetDefinition(name string) (bean.Definition, bool) {
+	d, ok := v.Container.GetDefinition(name)
+	if !ok || !v.contains(d) {
+		return nil, false
+	}
+	return d, true
+}
+
+func (v *conditionView) Scan(f func(key string, value bean.Definition) bool) {
+	v.Container.Scan(func(key string, value bean.Definition) bool {
+		if !v.contains(value) {
+			return true
+		}
+		return f(key, value)
+	})
+}
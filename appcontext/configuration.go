@@ -0,0 +1,136 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	configurationMethodSuffix = "Bean"
+)
+
+// 配置类，在第一次调用bean方法或者启动注入时完成配置类本身的注入及分类
+type configurationBean struct {
+	d    bean.Definition
+	once sync.Once
+	// 配置类提供的bean -> bean方法名称
+	beans map[bean.Definition]string
+}
+
+func isConfiguration(o interface{}, opts *registerOptions) bool {
+	if _, ok := o.(bean.BeanProvider); ok {
+		return true
+	}
+	return opts.configuration
+}
+
+// 获得配置类的bean方法：实现BeanProvider时使用BeanMethods，否则使用名称以Bean结尾的公开方法
+func configurationMethods(o interface{}) []bean.BeanMethod {
+	if p, ok := o.(bean.BeanProvider); ok {
+		return p.BeanMethods()
+	}
+	var ret []bean.BeanMethod
+	t := reflect.TypeOf(o)
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if len(m.Name) > len(configurationMethodSuffix) && strings.HasSuffix(m.Name, configurationMethodSuffix) {
+			ret = append(ret, bean.BeanMethod{
+				Method: m.Name,
+			})
+		}
+	}
+	return ret
+}
+
+// 注册配置类的bean方法，bean方法返回的对象依赖配置类
+func (ctx *defaultApplicationContext) registerConfiguration(name string, o interface{}, d bean.Definition, opts *registerOptions) error {
+	cfg := &configurationBean{
+		d:     d,
+		beans: map[bean.Definition]string{},
+	}
+	ctx.configLock.Lock()
+	ctx.configurations[d] = cfg
+	ctx.configLock.Unlock()
+
+	v := reflect.ValueOf(o)
+	for _, m := range configurationMethods(o) {
+		fn := v.MethodByName(m.Method)
+		if !fn.IsValid() {
+			return fmt.Errorf("Configuration %s method %s not found. ", name, m.Method)
+		}
+		ft := fn.Type()
+		wrapper := reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+			ctx.prepareConfiguration(cfg)
+			return fn.Call(args)
+		}).Interface()
+		if len(m.InjectNames) > 0 {
+			wrapper = bean.NewCustomBeanFactoryWithName(wrapper, m.InjectNames, "", "")
+		}
+
+		// 配置类的bean默认为单例，同时继承配置类的注册条件
+		regOpts := append([]bean.RegisterOpt{bean.SetScope(bean.Singleton)}, m.Opts...)
+		for _, c := range opts.conditions {
+			regOpts = append(regOpts, bean.OnCondition(c))
+		}
+		beanName := m.Name
+		if beanName == "" {
+			if ft.NumOut() == 0 {
+				return fmt.Errorf("Configuration %s method %s without return value. ", name, m.Method)
+			}
+			beanName = reflection.GetTypeName(ft.Out(0))
+		}
+		// 启动之后注册的配置类动态注册bean方法
+		err := ctx.RegisterBeanDynamic(beanName, wrapper, regOpts...)
+		if err != nil {
+			return fmt.Errorf("Configuration %s method %s register failed: %v ", name, m.Method, err)
+		}
+		if md, ok := ctx.container.GetDefinition(beanName); ok {
+			ctx.graph.addEdge(md, d, injector.InjectPoint{Function: m.Method, Index: -1})
+			ctx.configLock.Lock()
+			cfg.beans[md] = m.Method
+			ctx.configLock.Unlock()
+		}
+	}
+	return nil
+}
+
+func (ctx *defaultApplicationContext) configurationOf(d bean.Definition) *configurationBean {
+	ctx.configLock.Lock()
+	defer ctx.configLock.Unlock()
+
+	return ctx.configurations[d]
+}
+
+// 注入配置类并进行分类（如ValueProcessor的属性值注入），仅执行一次
+func (ctx *defaultApplicationContext) prepareConfiguration(cfg *configurationBean) {
+	cfg.once.Do(func() {
+		if !ctx.disableInject {
+			err := ctx.injector.Inject(ctx.graph.container(ctx.container, cfg.d), cfg.d.Interface())
+			if err != nil {
+				ctx.logger.Errorln("Inject failed: ", err)
+			}
+		}
+		ctx.classifyOneBean(cfg.d)
+	})
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"strings"
+)
+
+const (
+	// 最多报告的循环依赖数量
+	maxReportCycles = 64
+)
+
+// 启动时静态检测到的循环依赖，每个循环为完整的依赖路径：第一个依赖的From与最后一个依赖的To相同
+type CircularDependencyError struct {
+	Cycles [][]BeanEdge
+}
+
+func (e *CircularDependencyError) Error() string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("Circular dependency detected, %d cycle(s):", len(e.Cycles)))
+	for _, c := range e.Cycles {
+		buf.WriteString("\n  ")
+		buf.WriteString(cyclePath(c))
+		for _, edge := range c {
+			point := injector.InjectPoint{Field: edge.Field, Function: edge.Function, Index: edge.Index}
+			buf.WriteString(fmt.Sprintf("\n    %s [%s] -> %s", edge.From, point.String(), edge.To))
+		}
+	}
+	buf.WriteString(" ")
+	return buf.String()
+}
+
+// 循环依赖的路径，如：a -> b -> a
+func cyclePath(c []BeanEdge) string {
+	names := make([]string, 0, len(c)+1)
+	for _, edge := range c {
+		names = append(names, edge.From)
+	}
+	names = append(names, c[len(c)-1].To)
+	return strings.Join(names, " -> ")
+}
+
+type staticEdge struct {
+	to    bean.Definition
+	point injector.InjectPoint
+	// 普通对象的字段依赖，在对象创建之后注入
+	field bool
+}
+
+// 启动注入之前静态分析对象之间的依赖并检测循环依赖，c为分析依赖时使用的容器，defs为参与检测的对象定义
+// 创建对象时的依赖形成的循环返回CircularDependencyError，包括：
+// 1、构造方法的参数（CustomBeanFactory使用InjectNames匹配）
+// 2、配置类使用inject标记的字段，以及配置类提供的bean对配置类的依赖
+// 普通对象的字段在对象创建之后注入（延迟初始化时沿创建链获取正在初始化的单例），
+// 经过字段依赖的循环不会导致创建失败，作为警告返回
+func (ctx *defaultApplicationContext) checkCycles(c bean.Container, defs []bean.Definition) (warnings [][]BeanEdge, err error) {
+	index := make(map[bean.Definition]int, len(defs))
+	for i, d := range defs {
+		index[d] = i
+	}
+	deps := make(map[bean.Definition][]staticEdge, len(defs))
+	creation := make(map[bean.Definition][]staticEdge, len(defs))
+	add := func(from bean.Definition, points []injector.Dependency, field bool) {
+		for _, p := range points {
+			if _, ok := index[p.Definition]; !ok {
+				continue
+			}
+			dup := false
+			for _, e := range deps[from] {
+				if e.to == p.Definition {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				e := staticEdge{to: p.Definition, point: p.Point, field: field}
+				deps[from] = append(deps[from], e)
+				if !field {
+					creation[from] = append(creation[from], e)
+				}
+			}
+		}
+	}
+	for _, d := range defs {
+		if cfg := ctx.configurationOf(d); cfg != nil {
+			add(d, injector.FieldDependencies(c, d.Interface(), ctx.injectLicMgr), false)
+			ctx.configLock.Lock()
+			for md, m := range cfg.beans {
+				add(md, []injector.Dependency{{Point: injector.InjectPoint{Function: m, Index: -1}, Definition: d}}, false)
+			}
+			ctx.configLock.Unlock()
+		} else if d.IsObject() {
+			add(d, injector.FieldDependencies(c, d.Interface(), ctx.injectLicMgr), true)
+		} else {
+			if f := ctx.graph.factory(d); f != nil {
+				add(d, injector.FactoryDependencies(c, f, ctx.injectLicMgr), false)
+			}
+		}
+	}
+
+	// 仅由创建时的依赖形成的循环已经作为错误返回
+	for _, cycle := range findCycles(defs, index, deps) {
+		for _, e := range cycle[1:] {
+			if e.field {
+				warnings = append(warnings, ctx.cycleEdges(cycle))
+				break
+			}
+		}
+	}
+	cycles := findCycles(defs, index, creation)
+	if len(cycles) == 0 {
+		return warnings, nil
+	}
+	cerr := &CircularDependencyError{}
+	for _, cycle := range cycles {
+		cerr.Cycles = append(cerr.Cycles, ctx.cycleEdges(cycle))
+	}
+	return warnings, cerr
+}
+
+// 将findCycles返回的循环转换为依赖路径
+func (ctx *defaultApplicationContext) cycleEdges(cycle []staticEdge) []BeanEdge {
+	edges := make([]BeanEdge, 0, len(cycle))
+	from := cycle[0].to
+	for _, e := range cycle[1:] {
+		edges = append(edges, BeanEdge{
+			From:     ctx.graph.name(from),
+			To:       ctx.graph.name(e.to),
+			Field:    e.point.Field,
+			Function: e.point.Function,
+			Index:    e.point.Index,
+		})
+		from = e.to
+	}
+	return edges
+}
+
+// 查找所有的简单循环（最多maxReportCycles个），每个循环以其中顺序最靠前的对象开始
+// 返回的循环第一个元素仅包含起点，之后的每个元素为一次依赖
+func findCycles(defs []bean.Definition, index map[bean.Definition]int, deps map[bean.Definition][]staticEdge) [][]staticEdge {
+	scc := stronglyConnected(defs, index, deps)
+	var ret [][]staticEdge
+	for root, start := range defs {
+		path := []staticEdge{{to: start}}
+		onPath := map[bean.Definition]bool{start: true}
+		var visit func(d bean.Definition)
+		visit = func(d bean.Definition) {
+			for _, e := range deps[d] {
+				if len(ret) >= maxReportCycles {
+					return
+				}
+				i := index[e.to]
+				// 仅在同一个强连通分量中查找，且不经过顺序更靠前的对象（以其为起点的循环已经找到）
+				if i < root || scc[i] != scc[root] {
+					continue
+				}
+				if e.to == start {
+					c := make([]staticEdge, len(path), len(path)+1)
+					copy(c, path)
+					ret = append(ret, append(c, e))
+					continue
+				}
+				if onPath[e.to] {
+					continue
+				}
+				onPath[e.to] = true
+				path = append(path, e)
+				visit(e.to)
+				path = path[:len(path)-1]
+				onPath[e.to] = false
+			}
+		}
+		visit(start)
+	}
+	return ret
+}
+
+// 计算强连通分量（Tarjan），返回每个对象所属分量的编号
+func stronglyConnected(defs []bean.Definition, index map[bean.Definition]int, deps map[bean.Definition][]staticEdge) []int {
+	n := len(defs)
+	comp := make([]int, n)
+	low := make([]int, n)
+	order := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range order {
+		order[i] = -1
+	}
+	var stack []int
+	counter, compCount := 0, 0
+	var connect func(v int)
+	connect = func(v int) {
+		order[v] = counter
+		low[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, e := range deps[defs[v]] {
+			w := index[e.to]
+			if order[w] == -1 {
+				connect(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] && order[w] < low[v] {
+				low[v] = order[w]
+			}
+		}
+		if low[v] == order[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp[w] = compCount
+				if w == v {
+					break
+				}
+			}
+			compCount++
+		}
+	}
+	for i := range defs {
+		if order[i] == -1 {
+			connect(i)
+		}
+	}
+	return comp
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/xfali/neve-core/processor"
 	"github.com/xfali/neve-core/version"
 	"github.com/xfali/xlog"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -52,11 +54,31 @@ type defaultApplicationContext struct {
 	ctxAwareLock sync.Mutex
 
 	processors     []processor.Processor
+	postProcessors []processor.BeanPostProcessor
 	processorsLock sync.Mutex
 
+	conditionalBeans []*conditionalBean
+	conditionalLock  sync.Mutex
+
+	graph *dependencyRecorder
+
+	configurations map[bean.Definition]*configurationBean
+	configLock     sync.Mutex
+
+	// 按初始化顺序记录已初始化的对象定义，销毁时按相反顺序执行
+	initialized    []bean.Definition
+	initializedSet map[bean.Definition]struct{}
+	initLock       sync.Mutex
+
+	parent         ApplicationContext
+	propagateEvent bool
+
 	appName       string
 	disableInject bool
 	disableEvent  bool
+	lazyInit      bool
+	workers       int
+	profiles      []string
 	curState      int32
 
 	closeOnce sync.Once
@@ -67,6 +89,11 @@ func NewDefaultApplicationContext(opts ...Opt) *defaultApplicationContext {
 		logger:    xlog.GetLogger(),
 		container: bean.NewContainer(),
 		eventProc: NewEventProcessor(),
+		graph:     newDependencyRecorder(),
+
+		configurations: map[bean.Definition]*configurationBean{},
+
+		initializedSet: map[bean.Definition]struct{}{},
 
 		curState: statusNone,
 	}
@@ -133,8 +160,17 @@ func OptDisableEvent() Opt {
 
 func (ctx *defaultApplicationContext) Init(config fig.Properties) (err error) {
 	ctx.config = config
+	if setter, ok := ctx.injector.(injector.PropertiesSetter); ok {
+		setter.SetProperties(config)
+	}
 	ctx.appName = ctx.config.Get("neve.application.name", "Neve Application")
 	ctx.disableInject = ctx.config.Get("neve.inject.disable", "false") == "true"
+	ctx.lazyInit = ctx.config.Get("neve.application.lazyInit", "false") == "true"
+	ctx.workers = parseWorkers(ctx.config.Get(ConfigKeyInjectWorkers, strconv.Itoa(defaultInjectWorkers)))
+	ctx.profiles = ActiveProfiles(ctx.config)
+	if len(ctx.profiles) > 0 {
+		ctx.logger.Infof("Active profiles: %s\n", strings.Join(ctx.profiles, ","))
+	}
 
 	event := ctx.config.Get("neve.application.eventMode", "on")
 	event = strings.ToLower(event)
@@ -147,6 +183,12 @@ func (ctx *defaultApplicationContext) Init(config fig.Properties) (err error) {
 	}
 	// Register ApplicationEventPublisher
 	ctx.container.Register(ctx.eventProc.(ApplicationEventPublisher))
+	if ctx.parent != nil && ctx.propagateEvent && !ctx.disableEvent {
+		ctx.eventProc.AddListeners(&parentEventListener{
+			ctx:    ctx,
+			parent: ctx.parent,
+		})
+	}
 
 	return ctx.eventProc.Start()
 }
@@ -173,6 +215,10 @@ func (ctx *defaultApplicationContext) isInitializing() bool {
 	return atomic.LoadInt32(&ctx.curState) == statusInitializing
 }
 
+func (ctx *defaultApplicationContext) isInitialized() bool {
+	return atomic.LoadInt32(&ctx.curState) == statusInitialized
+}
+
 func (ctx *defaultApplicationContext) RegisterBean(o interface{}, opts ...bean.RegisterOpt) error {
 	return ctx.RegisterBeanByName("", o, opts...)
 }
@@ -182,31 +228,117 @@ func (ctx *defaultApplicationContext) RegisterBeanByName(name string, o interfac
 		return errors.New("Initializing, cannot register new object. ")
 	}
 
+	if ctx.isFrozen() {
+		return bean.ErrContainerFrozen
+	}
+
 	if o == nil {
 		return nil
 	}
-	var err error
-	o, err = injector.WrapBean(o, ctx.container, ctx.injector, ctx.injectLicMgr)
-	if err != nil {
+	r, err := ctx.prepareRegister(o, opts)
+	if r == nil || err != nil {
 		return err
 	}
 
 	if name == "" {
-		err = ctx.container.Register(o, opts...)
+		err = ctx.container.Register(r.o, r.opts...)
 	} else {
-		err = ctx.container.RegisterByName(name, o, opts...)
+		err = ctx.container.RegisterByName(name, r.o, r.opts...)
 	}
 	if err != nil {
 		return err
 	}
 
+	if name == "" {
+		d, err := bean.CreateBeanDefinition(r.o)
+		if err != nil {
+			return err
+		}
+		name = d.Name()
+	}
+	if d, ok := ctx.container.GetDefinition(name); ok {
+		ctx.graph.addNode(name, d, r.regOpts.order)
+		ctx.completeRegister(r, d)
+		if d.IsObject() && isConfiguration(r.o, r.regOpts) {
+			err = ctx.registerConfiguration(name, r.o, d, r.regOpts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// 配置了注册条件的bean在启动时判断条件之后再处理
+	if len(r.regOpts.conditions) > 0 {
+		ctx.addConditionalBean(name, r.o, r.rc, r.regOpts)
+		return nil
+	}
+
+	return ctx.postRegister(r.o, r.rc, r.regOpts.lazy)
+}
+
+// 注册中的对象
+type beanRegistration struct {
+	// 包装后注册到容器的对象
+	o interface{}
+	// 包装前的对象，用于静态分析构造方法的依赖
+	factory interface{}
+	// 记录对象依赖的容器，依赖方在注册之后设置
+	rc      *recordContainer
+	opts    []bean.RegisterOpt
+	regOpts *registerOptions
+}
+
+// 解析注册配置并包装对象：判断profiles、全局延迟初始化、设置构造方法参数的注入名称
+// profiles未激活时返回nil，跳过注册
+func (ctx *defaultApplicationContext) prepareRegister(o interface{}, opts []bean.RegisterOpt) (*beanRegistration, error) {
+	regOpts := parseRegisterOpts(opts...)
+	if !matchProfiles(ctx.profiles, regOpts.profiles) {
+		ctx.logger.Infof("Bean profiles [%s] not active, skip registration\n", strings.Join(regOpts.profiles, ","))
+		return nil, nil
+	}
+	if !regOpts.lazy && ctx.lazyInit && ctx.canLazy(o) {
+		opts = append(opts, bean.SetLazy())
+		regOpts.lazy = true
+	}
+
+	o, err := withInjectNames(o, regOpts.injectNames)
+	if err != nil {
+		return nil, err
+	}
+	rc := ctx.graph.container(ctx.container, nil)
+	factory := o
+	o, err = injector.WrapBeanWithCreation(o, rc, ctx.injector, ctx.injectLicMgr)
+	if err != nil {
+		return nil, err
+	}
+	return &beanRegistration{
+		o:       o,
+		factory: factory,
+		rc:      rc,
+		opts:    opts,
+		regOpts: regOpts,
+	}, nil
+}
+
+// 对象注册到容器之后设置依赖方及构造方法
+func (ctx *defaultApplicationContext) completeRegister(r *beanRegistration, d bean.Definition) {
+	r.rc.owner = d
+	if !d.IsObject() {
+		ctx.graph.setFactory(d, r.factory)
+	}
+}
+
+func (ctx *defaultApplicationContext) postRegister(o interface{}, c bean.Container, lazy bool) error {
 	if !ctx.disableEvent {
 		ctx.eventProc.AddListeners(o)
 	}
 
-	err = ctx.classifyInjectFunction(o)
-	if err != nil {
-		return err
+	// 延迟初始化的对象在初始化时再进行方法注入
+	if !lazy {
+		err := ctx.classifyInjectFunction(o, c)
+		if err != nil {
+			return err
+		}
 	}
 
 	if v, ok := o.(ApplicationContextAware); ok {
@@ -214,15 +346,28 @@ func (ctx *defaultApplicationContext) RegisterBeanByName(name string, o interfac
 	}
 
 	if v, ok := o.(processor.Processor); ok {
-		err = ctx.addProcessor(v, true)
+		err := ctx.addProcessor(v, true)
 		if err != nil {
 			return err
 		}
 	}
 
+	if v, ok := o.(processor.BeanPostProcessor); ok {
+		ctx.addPostProcessor(v)
+	}
+
 	return nil
 }
 
+// 全局延迟初始化时，Processor、BeanPostProcessor及事件监听器需要在启动时初始化
+func (ctx *defaultApplicationContext) canLazy(o interface{}) bool {
+	switch o.(type) {
+	case processor.Processor, processor.BeanPostProcessor, ApplicationEventListener, ApplicationEventConsumer:
+		return false
+	}
+	return true
+}
+
 func (ctx *defaultApplicationContext) addAware(aware ApplicationContextAware, withLock bool) {
 	if !withLock {
 		ctx.ctxAwares = append(ctx.ctxAwares, aware)
@@ -247,19 +392,76 @@ func (ctx *defaultApplicationContext) addProcessor(p processor.Processor, withLo
 	return p.Init(ctx.config, ctx.container)
 }
 
-func (ctx *defaultApplicationContext) classifyInjectFunction(o interface{}) error {
+func (ctx *defaultApplicationContext) classifyInjectFunction(o interface{}, c bean.Container) error {
 	if v, ok := o.(injector.InjectFunction); ok {
-		return v.RegisterFunction(ctx.funcHandler)
+		return v.RegisterFunction(injector.WithContainer(ctx.funcHandler, c))
 	}
 	return nil
 }
 
 func (ctx *defaultApplicationContext) GetBean(name string) (interface{}, bool) {
-	return ctx.container.Get(name)
+	d, ok := ctx.container.GetDefinition(name)
+	if !ok {
+		return nil, false
+	}
+	v, err := definitionValue(d)
+	if err != nil {
+		ctx.logger.Errorln(err)
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// 获得对象定义的值，构造方法返回错误时返回bean.CreateBeanError
+func definitionValue(d bean.Definition) (v reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*bean.CreateBeanError)
+			if !ok {
+				panic(r)
+			}
+			err = e
+		}
+	}()
+	return d.Value(), nil
 }
 
 func (ctx *defaultApplicationContext) GetBeanByType(o interface{}) bool {
-	return ctx.container.GetByType(o)
+	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	v = v.Elem()
+	d, err := ctx.ResolveBean("", v.Type())
+	if err != nil {
+		return false
+	}
+	dv, err := definitionValue(d)
+	if err != nil {
+		ctx.logger.Errorln(err)
+		return false
+	}
+	if !dv.IsValid() {
+		return false
+	}
+	v.Set(dv)
+	return true
+}
+
+func (ctx *defaultApplicationContext) ResolveBean(name string, t reflect.Type) (bean.Definition, error) {
+	return injector.Resolve(ctx.container, name, t)
+}
+
+func (ctx *defaultApplicationContext) ResolveBeans(t reflect.Type) []bean.Definition {
+	return injector.ResolveAll(ctx.container, t)
+}
+
+func (ctx *defaultApplicationContext) NewScope(c context.Context) BeanScope {
+	return newBeanScope(ctx, c)
+}
+
+func (ctx *defaultApplicationContext) DependencyGraph() *DependencyGraph {
+	return ctx.graph.graph()
 }
 
 func (ctx *defaultApplicationContext) AddProcessor(p processor.Processor) error {
@@ -285,13 +487,43 @@ func (ctx *defaultApplicationContext) SendEvent(e ApplicationEvent) error {
 	return ctx.eventProc.SendEvent(e)
 }
 
-func (ctx *defaultApplicationContext) Start() error {
+func (ctx *defaultApplicationContext) Start() (err error) {
 	ctx.printCtxInfo()
 	// 第一次初始化，注入所有对象
 	if atomic.CompareAndSwapInt32(&ctx.curState, statusNone, statusInitializing) {
+		// 构造方法返回的错误导致启动失败
+		defer func() {
+			if r := recover(); r != nil {
+				e, ok := r.(*bean.CreateBeanError)
+				if !ok {
+					panic(r)
+				}
+				err = fmt.Errorf("Start failed: create bean [%s] failed: %w ", ctx.graph.name(e.Definition), e.Err)
+			}
+		}()
+
+		// Check bean conditions
+		err = ctx.checkConditions()
+		if err != nil {
+			return err
+		}
+
+		// 静态检测构造方法之间的循环依赖，经过字段依赖的循环不影响启动
+		var fieldCycles [][]BeanEdge
+		fieldCycles, err = ctx.checkCycles(ctx.container, ctx.definitions())
+		if err != nil {
+			return fmt.Errorf("Start failed: %w", err)
+		}
+		for _, c := range fieldCycles {
+			ctx.logger.Debugf("Circular dependency through fields: %s\n", cyclePath(c))
+		}
+
 		// ApplicationContextAware Set.
 		ctx.notifyAware()
 
+		// Lazy init beans
+		ctx.prepareLazyBeans()
+
 		// Inject Beans
 		ctx.injectAll()
 		// Processor classify
@@ -302,6 +534,8 @@ func (ctx *defaultApplicationContext) Start() error {
 		ctx.notifyBeanSet()
 		// Processor process
 		ctx.doProcess()
+		// 冻结容器
+		ctx.freeze()
 
 		// 初始化完成
 		if !atomic.CompareAndSwapInt32(&ctx.curState, statusInitializing, statusInitialized) {
@@ -331,21 +565,89 @@ func (ctx *defaultApplicationContext) notifyAware() {
 	}
 }
 
+func (ctx *defaultApplicationContext) prepareLazyBeans() {
+	ctx.scanLocal(func(key string, value bean.Definition) bool {
+		if bean.IsLazy(value) {
+			if l, ok := value.(bean.LazyInitializer); ok {
+				l.SetLazyInit(ctx.lazyInitBean)
+			}
+		}
+		return true
+	})
+}
+
+// 延迟初始化的对象在第一次获取时进行注入、分类及初始化
+func (ctx *defaultApplicationContext) lazyInitBean(d bean.Definition, v reflect.Value, chain *bean.Creation) {
+	if !d.IsObject() {
+		err := ctx.initDefinition(d, func() error {
+			return ctx.initInstance(d, v)
+		})
+		if err != nil {
+			ctx.logger.Errorln(err)
+		}
+		return
+	}
+
+	if cfg := ctx.configurationOf(d); cfg != nil {
+		// 配置类可能已经在调用bean方法时完成注入及分类
+		ctx.prepareConfiguration(cfg)
+	} else {
+		if !ctx.disableInject {
+			ctx.injectObject(d, v.Interface(), chain)
+		}
+		ctx.classifyOneBean(d)
+	}
+	err := ctx.initDefinition(d, d.AfterSet)
+	if err != nil {
+		ctx.logger.Errorln(err)
+	}
+	ctx.addInitialized(d)
+}
+
+// 注入单个对象，包括字段注入及方法注入，chain为延迟初始化时的创建链
+func (ctx *defaultApplicationContext) injectObject(d bean.Definition, o interface{}, chain *bean.Creation) {
+	rc := injector.WithCreation(ctx.graph.container(ctx.container, d), chain)
+	err := ctx.injector.Inject(rc, o)
+	if err != nil {
+		ctx.logger.Errorln("Inject failed: ", err)
+	}
+	if f, ok := o.(injector.InjectFunction); ok {
+		handler := injector.NewDefaultInjectFunctionHandler(ctx.logger, ctx.injectLicMgr)
+		handler.SetInjector(ctx.injector)
+		err = f.RegisterFunction(injector.WithContainer(handler, rc))
+		if err == nil {
+			err = handler.InjectAllFunctions(ctx.container)
+		}
+		if err != nil {
+			ctx.logger.Errorln(err)
+		}
+	}
+}
+
+// 对所有对象进行分类，配置neve.inject.workers时并行执行（Classifier的实现应线程安全）
 func (ctx *defaultApplicationContext) classifyBean() {
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
+	ctx.runTasks(ctx.definitions(), nil, func(value bean.Definition) {
+		if bean.IsLazy(value) {
+			return
+		}
+		if cfg := ctx.configurationOf(value); cfg != nil {
+			ctx.prepareConfiguration(cfg)
+			return
+		}
 		//if value.IsObject() {
 		// 必须先分类，由于ValueProcessor会在Classify将配置的属性值注入
 		ctx.classifyOneBean(value)
 		//}
-		return true
 	})
 }
 
 func (ctx *defaultApplicationContext) classifyOneBean(o bean.Definition) {
 	ctx.processorsLock.Lock()
-	defer ctx.processorsLock.Unlock()
+	processors := make([]processor.Processor, len(ctx.processors))
+	copy(processors, ctx.processors)
+	ctx.processorsLock.Unlock()
 
-	for _, processor := range ctx.processors {
+	for _, processor := range processors {
 		_, err := o.Classify(processor)
 		//_, err := processor.Classify(o)
 		if err != nil {
@@ -354,14 +656,84 @@ func (ctx *defaultApplicationContext) classifyOneBean(o bean.Definition) {
 	}
 }
 
+// 初始化在容器启动之后创建的实例：使用Processor分类并执行初始化回调
+func (ctx *defaultApplicationContext) initInstance(d bean.Definition, v reflect.Value) error {
+	if !v.IsValid() {
+		return nil
+	}
+	ctx.processorsLock.Lock()
+	for _, processor := range ctx.processors {
+		_, err := processor.Classify(v.Interface())
+		if err != nil {
+			ctx.logger.Errorln(err)
+		}
+	}
+	ctx.processorsLock.Unlock()
+
+	if l, ok := d.(bean.InstanceLifecycle); ok {
+		return l.AfterSetInstance(v)
+	}
+	return nil
+}
+
+// 按依赖关系的拓扑顺序调用BeanAfterSet，order仅用于决定无依赖关系的对象的顺序
+// 配置neve.inject.workers时并行执行：对象在其依赖的对象以及order更小的对象初始化完成之后初始化
 func (ctx *defaultApplicationContext) notifyBeanSet() {
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		err := value.AfterSet()
+	defs, cycles := ctx.graph.sort(ctx.definitions())
+	for _, c := range cycles {
+		// 字段之间的循环依赖不影响初始化，仅影响初始化顺序
+		ctx.logger.Debugf("Circular dependency detected: %s\n", strings.Join(c, " -> "))
+	}
+	ctx.runTasks(defs, ctx.graph.dependencies(), func(d bean.Definition) {
+		if bean.IsLazy(d) {
+			return
+		}
+		err := ctx.initDefinition(d, d.AfterSet)
 		if err != nil {
 			ctx.logger.Errorln(err)
 		}
+		ctx.addInitialized(d)
+	})
+}
+
+// 获得容器中所有的对象定义（按order及注册顺序，去除重复的名称）
+func (ctx *defaultApplicationContext) definitions() []bean.Definition {
+	var ret []bean.Definition
+	set := map[bean.Definition]struct{}{}
+	ctx.scanLocal(func(key string, value bean.Definition) bool {
+		if _, ok := set[value]; !ok {
+			set[value] = struct{}{}
+			ret = append(ret, value)
+		}
 		return true
 	})
+	return ret
+}
+
+func (ctx *defaultApplicationContext) removeInitialized(d bean.Definition) {
+	ctx.initLock.Lock()
+	defer ctx.initLock.Unlock()
+
+	if _, ok := ctx.initializedSet[d]; !ok {
+		return
+	}
+	delete(ctx.initializedSet, d)
+	for i, v := range ctx.initialized {
+		if v == d {
+			ctx.initialized = append(ctx.initialized[:i], ctx.initialized[i+1:]...)
+			break
+		}
+	}
+}
+
+func (ctx *defaultApplicationContext) addInitialized(d bean.Definition) {
+	ctx.initLock.Lock()
+	defer ctx.initLock.Unlock()
+
+	if _, ok := ctx.initializedSet[d]; !ok {
+		ctx.initializedSet[d] = struct{}{}
+		ctx.initialized = append(ctx.initialized, d)
+	}
 }
 
 func (ctx *defaultApplicationContext) doProcess() {
@@ -387,29 +759,162 @@ func (ctx *defaultApplicationContext) doFunctionInject() {
 	}
 }
 
+// 注入所有对象，配置neve.inject.workers时并行执行，构造方法在注入依赖方时创建对象
 func (ctx *defaultApplicationContext) injectAll() {
 	if ctx.disableInject {
 		return
 	}
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		if value.IsObject() {
-			err := ctx.injector.Inject(ctx.container, value.Interface())
-			if err != nil {
-				ctx.logger.Errorln("Inject failed: ", err)
-			}
+	var parallel, serial []bean.Definition
+	for _, value := range ctx.definitions() {
+		if !value.IsObject() || bean.IsLazy(value) {
+			continue
+		}
+		if ctx.createsOnInject(value) {
+			serial = append(serial, value)
+		} else {
+			parallel = append(parallel, value)
+		}
+	}
+	inject := func(value bean.Definition) {
+		if cfg := ctx.configurationOf(value); cfg != nil {
+			ctx.prepareConfiguration(cfg)
+			return
 		}
+		err := ctx.injector.Inject(ctx.graph.container(ctx.container, value), value.Interface())
+		if err != nil {
+			ctx.logger.Errorln("Inject failed: ", err)
+		}
+	}
+	ctx.runTasks(parallel, nil, inject)
+	// 注入时会创建对象（延迟初始化、调用构造方法）的对象定义串行注入：
+	// 并行创建相互依赖的对象时，各任务以相反的顺序持有对象定义的创建锁会导致死锁
+	for _, value := range serial {
+		inject(value)
+	}
+}
+
+// 注入对象定义d时是否可能持有创建锁：配置类、依赖延迟初始化的对象或者构造方法创建的单例
+func (ctx *defaultApplicationContext) createsOnInject(d bean.Definition) bool {
+	if ctx.configurationOf(d) != nil {
 		return true
-	})
+	}
+	visited := map[bean.Definition]struct{}{}
+	for _, dep := range injector.FieldDependencies(ctx.container, d.Interface(), ctx.injectLicMgr) {
+		if ctx.locksOnCreate(dep.Definition, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// 获取对象定义d的值时是否可能持有创建锁，原型的构造方法继续判断其参数的依赖
+func (ctx *defaultApplicationContext) locksOnCreate(d bean.Definition, visited map[bean.Definition]struct{}) bool {
+	if _, ok := visited[d]; ok {
+		return false
+	}
+	visited[d] = struct{}{}
+	if d.IsObject() {
+		return bean.IsLazy(d)
+	}
+	if bean.ScopeOf(d) != bean.Prototype {
+		return true
+	}
+	f := ctx.graph.factory(d)
+	if f == nil {
+		return true
+	}
+	for _, dep := range injector.FactoryDependencies(ctx.container, f, ctx.injectLicMgr) {
+		if ctx.locksOnCreate(dep.Definition, visited) {
+			return true
+		}
+	}
+	return false
 }
 
+// 按初始化的相反顺序销毁对象，未初始化的对象最后按拓扑顺序的相反顺序销毁
 func (ctx *defaultApplicationContext) destroyBeans() {
-	ctx.container.Scan(func(key string, value bean.Definition) bool {
-		err := value.Destroy()
+	defs, _ := ctx.graph.sort(ctx.definitions())
+
+	ctx.initLock.Lock()
+	initialized := ctx.initialized
+	others := make([]bean.Definition, 0, len(defs))
+	for _, d := range defs {
+		if _, ok := ctx.initializedSet[d]; !ok {
+			others = append(others, d)
+		}
+	}
+	ctx.initLock.Unlock()
+	destroy := func(d bean.Definition) {
+		err := d.Destroy()
 		if err != nil {
 			ctx.logger.Errorln(err)
 		}
-		return true
-	})
+	}
+	for i := len(initialized) - 1; i >= 0; i-- {
+		destroy(initialized[i])
+	}
+	for i := len(others) - 1; i >= 0; i-- {
+		destroy(others[i])
+	}
+}
+
+// 注册配置，用于在注册时解析bean.RegisterOpt
+type registerOptions struct {
+	order      int
+	orderSet   bool
+	lazy       bool
+	conditions []bean.Condition
+	profiles   []string
+	// 是否为配置类
+	configuration bool
+	// 构造方法参数的注入名称
+	injectNames []string
+}
+
+func parseRegisterOpts(opts ...bean.RegisterOpt) *registerOptions {
+	ret := &registerOptions{}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
+
+func (o *registerOptions) Set(key string, value interface{}) {
+	switch key {
+	case bean.KeySetOrder:
+		o.order, o.orderSet = value.(int)
+	case bean.KeySetLazy:
+		o.lazy, _ = value.(bool)
+	case bean.KeySetCondition:
+		if v, ok := value.(bean.Condition); ok {
+			o.conditions = append(o.conditions, v)
+		}
+	case bean.KeySetConfig:
+		o.configuration, _ = value.(bool)
+	case bean.KeySetProfiles:
+		if v, ok := value.([]string); ok {
+			o.profiles = append(o.profiles, v...)
+		}
+	case bean.KeySetInjectNames:
+		o.injectNames, _ = value.([]string)
+	}
+}
+
+// 使用bean.SetInjectNames配置了参数注入名称的构造方法，转换为CustomBeanFactory
+func withInjectNames(o interface{}, names []string) (interface{}, error) {
+	if len(names) == 0 {
+		return o, nil
+	}
+	if _, ok := o.(bean.CustomBeanFactory); ok {
+		return nil, errors.New("CustomBeanFactory cannot set inject names by register option, use InjectNames instead. ")
+	}
+	if reflect.TypeOf(o).Kind() != reflect.Func {
+		return nil, errors.New("Only bean factory function can set inject names. ")
+	}
+	if _, err := bean.CreateBeanDefinition(o); err != nil {
+		return nil, err
+	}
+	return bean.NewCustomBeanFactoryWithName(o, names, "", ""), nil
 }
 
 func (ctx *defaultApplicationContext) notifyStarted() {
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"errors"
+	"github.com/xfali/neve-core/bean"
+)
+
+const (
+	ConfigKeyContainerFreeze = "neve.application.freeze"
+)
+
+// 启动完成后冻结容器，之后的读操作无锁
+func (ctx *defaultApplicationContext) freeze() {
+	if ctx.config.Get(ConfigKeyContainerFreeze, "true") != "true" {
+		return
+	}
+	if c, ok := ctx.container.(bean.FreezableContainer); ok {
+		c.Freeze()
+	}
+}
+
+func (ctx *defaultApplicationContext) isFrozen() bool {
+	if c, ok := ctx.container.(bean.FreezableContainer); ok {
+		return c.IsFrozen()
+	}
+	return false
+}
+
+func (ctx *defaultApplicationContext) RegisterBeanDynamic(name string, o interface{}, opts ...bean.RegisterOpt) error {
+	if !ctx.isInitialized() {
+		return ctx.RegisterBeanByName(name, o, opts...)
+	}
+	if o == nil {
+		return errors.New("Bean is nil. ")
+	}
+	r, err := ctx.prepareRegister(o, opts)
+	if r == nil || err != nil {
+		return err
+	}
+	if name == "" {
+		d, err := bean.CreateBeanDefinition(r.o)
+		if err != nil {
+			return err
+		}
+		name = d.Name()
+	}
+	if c, ok := ctx.container.(bean.FreezableContainer); ok {
+		err = c.RegisterDynamic(name, r.o, r.opts...)
+	} else {
+		err = ctx.container.RegisterByName(name, r.o, r.opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	d, ok := ctx.container.GetDefinition(name)
+	if !ok {
+		return errors.New(name + " bean not found after registered. ")
+	}
+	ctx.graph.addNode(name, d, r.regOpts.order)
+	ctx.completeRegister(r, d)
+
+	// 启动之后注册的bean立即判断条件
+	if !ctx.matchRegisterConditions(name, r.regOpts, d) {
+		bean.Remove(ctx.container, name)
+		ctx.graph.remove(d)
+		return nil
+	}
+	if d.IsObject() && isConfiguration(r.o, r.regOpts) {
+		err = ctx.registerConfiguration(name, r.o, d, r.regOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx.initRuntimeBean(r.o, d)
+	return nil
+}
+
+// 初始化启动之后注册的对象：添加事件监听器、装配ApplicationContext，非延迟初始化的对象立即完成注入及初始化
+// 注意：启动之后注册的Processor不会被添加到处理器列表
+func (ctx *defaultApplicationContext) initRuntimeBean(o interface{}, d bean.Definition) {
+	if !ctx.disableEvent {
+		ctx.eventProc.AddListeners(o)
+	}
+	if v, ok := o.(ApplicationContextAware); ok {
+		v.SetApplicationContext(ctx)
+	}
+	// 对象在获取时完成注入及初始化
+	if l, ok := d.(bean.LazyInitializer); ok {
+		l.SetLazyInit(ctx.lazyInitBean)
+	}
+	if d.IsObject() && !bean.IsLazy(d) {
+		d.Value()
+	}
+}
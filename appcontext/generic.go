@@ -0,0 +1,82 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+// 根据类型获得对象，T可以为接口或指针类型
+// 先按类型默认名称查找，不存在时自动匹配可以赋值给T的对象，多个候选对象时选择primary对象
+// 对象不存在或无法确定唯一对象时返回错误
+func Get[T any](ctx ApplicationContext) (T, error) {
+	var zero T
+	d, err := ResolveBean(ctx, "", typeOf[T]())
+	if err != nil {
+		return zero, err
+	}
+	return valueOf[T](d)
+}
+
+// 根据名称（或别名）获得对象，对象不存在或类型无法赋值给T时返回错误
+func GetNamed[T any](ctx ApplicationContext, name string) (T, error) {
+	var zero T
+	if name == "" {
+		return zero, fmt.Errorf("Bean name is empty. ")
+	}
+	d, err := ResolveBean(ctx, name, typeOf[T]())
+	if err != nil {
+		return zero, err
+	}
+	return valueOf[T](d)
+}
+
+// 获得所有可以赋值给T的对象（包括指定名称注册的对象），按order及注册顺序排列
+func GetAll[T any](ctx ApplicationContext) []T {
+	defs := ResolveBeans(ctx, typeOf[T]())
+	ret := make([]T, 0, len(defs))
+	for _, d := range defs {
+		v, err := valueOf[T](d)
+		if err == nil {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func valueOf[T any](d bean.Definition) (T, error) {
+	var zero T
+	v, err := definitionValue(d)
+	if err != nil {
+		return zero, err
+	}
+	if !v.IsValid() {
+		return zero, fmt.Errorf("Bean %s value is invalid. ", reflection.GetTypeName(d.Type()))
+	}
+	ret, ok := v.Interface().(T)
+	if !ok {
+		return zero, fmt.Errorf("Bean %s cannot assign to %s. ", reflection.GetTypeName(d.Type()), reflection.GetTypeName(typeOf[T]()))
+	}
+	return ret, nil
+}
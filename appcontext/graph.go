@@ -0,0 +1,519 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"github.com/xfali/neve-core/reflection"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// 依赖图中的bean节点
+type BeanNode struct {
+	// 注册名称
+	Name string `json:"name"`
+	// 类型名称
+	Type string `json:"type"`
+	// 注册时配置的order
+	Order int `json:"order"`
+}
+
+// 依赖图中的依赖关系：From依赖To
+type BeanEdge struct {
+	// 依赖方（被注入的bean）名称
+	From string `json:"from"`
+	// 被依赖方（注入的bean）名称
+	To string `json:"to"`
+	// 注入的字段名称，方法参数注入时为空
+	Field string `json:"field,omitempty"`
+	// 注入的方法名称，字段注入及构造方法参数注入时为空
+	Function string `json:"function,omitempty"`
+	// 注入的方法参数序号，字段注入时为-1
+	Index int `json:"index"`
+}
+
+// bean依赖图，在ApplicationContext启动注入时记录
+type DependencyGraph struct {
+	Nodes []BeanNode `json:"nodes"`
+	Edges []BeanEdge `json:"edges"`
+}
+
+// 以JSON格式输出依赖图
+func (g *DependencyGraph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// 以Graphviz DOT格式输出依赖图
+func (g *DependencyGraph) WriteDOT(w io.Writer) error {
+	buf := bytes.Buffer{}
+	buf.WriteString("digraph beans {\n")
+	buf.WriteString("  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := fmt.Sprintf("%s\n%s\norder: %d", n.Name, n.Type, n.Order)
+		buf.WriteString(fmt.Sprintf("  %s [label=%s];\n", strconv.Quote(n.Name), strconv.Quote(label)))
+	}
+	for _, e := range g.Edges {
+		label := injector.InjectPoint{Field: e.Field, Function: e.Function, Index: e.Index}.String()
+		buf.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strconv.Quote(label)))
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// 返回DOT格式的依赖图
+func (g *DependencyGraph) DOT() string {
+	buf := bytes.Buffer{}
+	_ = g.WriteDOT(&buf)
+	return buf.String()
+}
+
+// 返回JSON格式的依赖图
+func (g *DependencyGraph) JSON() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+type graphNode struct {
+	name  string
+	d     bean.Definition
+	order int
+	// 注册的构造方法（包装注入之前），用于启动时静态检测循环依赖
+	factory interface{}
+}
+
+type graphEdge struct {
+	from  bean.Definition
+	to    bean.Definition
+	point injector.InjectPoint
+}
+
+// 依赖记录器，按注册顺序保存bean节点以及注入时记录的依赖关系
+type dependencyRecorder struct {
+	nodes    []*graphNode
+	nodeMap  map[bean.Definition]*graphNode
+	edges    []graphEdge
+	edgeKeys map[graphEdge]struct{}
+	lock     sync.Mutex
+}
+
+func newDependencyRecorder() *dependencyRecorder {
+	return &dependencyRecorder{
+		nodeMap:  map[bean.Definition]*graphNode{},
+		edgeKeys: map[graphEdge]struct{}{},
+	}
+}
+
+func (r *dependencyRecorder) addNode(name string, d bean.Definition, order int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.addNodeLocked(name, d, order)
+}
+
+func (r *dependencyRecorder) addNodeLocked(name string, d bean.Definition, order int) *graphNode {
+	if n, ok := r.nodeMap[d]; ok {
+		return n
+	}
+	n := &graphNode{
+		name:  name,
+		d:     d,
+		order: order,
+	}
+	r.nodes = append(r.nodes, n)
+	r.nodeMap[d] = n
+	return n
+}
+
+// 记录对象定义注册时的构造方法（或CustomBeanFactory）
+func (r *dependencyRecorder) setFactory(d bean.Definition, factory interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if n, ok := r.nodeMap[d]; ok {
+		n.factory = factory
+	}
+}
+
+// 获得对象定义注册时的构造方法，不存在时返回nil
+func (r *dependencyRecorder) factory(d bean.Definition) interface{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if n, ok := r.nodeMap[d]; ok {
+		return n.factory
+	}
+	return nil
+}
+
+func (r *dependencyRecorder) remove(d bean.Definition) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.nodeMap[d]; !ok {
+		return
+	}
+	delete(r.nodeMap, d)
+	nodes := r.nodes[:0]
+	for _, n := range r.nodes {
+		if n.d != d {
+			nodes = append(nodes, n)
+		}
+	}
+	r.nodes = nodes
+	r.removeEdgesLocked(d)
+}
+
+// 使用新的对象定义替换节点，保留节点名称，并移除与原对象定义相关的依赖关系
+func (r *dependencyRecorder) replace(old, d bean.Definition, order int, orderSet bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	n, ok := r.nodeMap[old]
+	if !ok {
+		r.addNodeLocked(d.Name(), d, order)
+		return
+	}
+	delete(r.nodeMap, old)
+	n.d = d
+	n.factory = nil
+	if orderSet {
+		n.order = order
+	}
+	r.nodeMap[d] = n
+	r.removeEdgesLocked(old)
+}
+
+func (r *dependencyRecorder) removeEdgesLocked(d bean.Definition) {
+	edges := r.edges[:0]
+	for _, e := range r.edges {
+		if e.from == d || e.to == d {
+			delete(r.edgeKeys, e)
+		} else {
+			edges = append(edges, e)
+		}
+	}
+	r.edges = edges
+}
+
+// 获得依赖d的对象定义
+func (r *dependencyRecorder) dependents(d bean.Definition) []bean.Definition {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var ret []bean.Definition
+	set := map[bean.Definition]struct{}{}
+	for _, e := range r.edges {
+		if e.to == d && e.from != d {
+			if _, ok := set[e.from]; !ok {
+				set[e.from] = struct{}{}
+				ret = append(ret, e.from)
+			}
+		}
+	}
+	return ret
+}
+
+// 获得对象定义之间的依赖关系：依赖方 -> 被依赖方（去除自身依赖）
+func (r *dependencyRecorder) dependencies() map[bean.Definition][]bean.Definition {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := map[bean.Definition][]bean.Definition{}
+	for _, e := range r.edges {
+		if e.from != e.to {
+			ret[e.from] = append(ret[e.from], e.to)
+		}
+	}
+	return ret
+}
+
+// 获得对象定义注册时配置的order，未记录时返回0
+func (r *dependencyRecorder) order(d bean.Definition) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if n, ok := r.nodeMap[d]; ok {
+		return n.order
+	}
+	return 0
+}
+
+func (r *dependencyRecorder) addEdge(from, to bean.Definition, point injector.InjectPoint) {
+	if from == nil || to == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e := graphEdge{
+		from:  from,
+		to:    to,
+		point: point,
+	}
+	if _, ok := r.edgeKeys[e]; ok {
+		return
+	}
+	r.edgeKeys[e] = struct{}{}
+	// 未通过ApplicationContext注册的对象（如内置对象）使用默认名称
+	r.addNodeLocked(from.Name(), from, 0)
+	r.addNodeLocked(to.Name(), to, 0)
+	r.edges = append(r.edges, e)
+}
+
+func (r *dependencyRecorder) graph() *DependencyGraph {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := &DependencyGraph{
+		Nodes: make([]BeanNode, 0, len(r.nodes)),
+		Edges: make([]BeanEdge, 0, len(r.edges)),
+	}
+	for _, n := range r.nodes {
+		ret.Nodes = append(ret.Nodes, BeanNode{
+			Name:  n.name,
+			Type:  reflection.GetTypeName(n.d.Type()),
+			Order: n.order,
+		})
+	}
+	for _, e := range r.edges {
+		ret.Edges = append(ret.Edges, BeanEdge{
+			From:     r.nodeMap[e.from].name,
+			To:       r.nodeMap[e.to].name,
+			Field:    e.point.Field,
+			Function: e.point.Function,
+			Index:    e.point.Index,
+		})
+	}
+	return ret
+}
+
+// 为容器绑定依赖方，使用该容器注入时记录依赖关系
+func (r *dependencyRecorder) container(c bean.Container, owner bean.Definition) *recordContainer {
+	return &recordContainer{
+		Container: c,
+		recorder:  r,
+		owner:     owner,
+	}
+}
+
+type recordContainer struct {
+	bean.Container
+	recorder *dependencyRecorder
+	owner    bean.Definition
+}
+
+func (c *recordContainer) RecordDependency(point injector.InjectPoint, d bean.Definition) {
+	c.recorder.addEdge(c.owner, d, point)
+}
+
+// 以下方法转发到被包装的容器，保留容器实现的可选接口
+
+func (c *recordContainer) Alias(name, alias string) error {
+	return bean.Alias(c.Container, name, alias)
+}
+
+func (c *recordContainer) Replace(name string, o interface{}, opts ...bean.RegisterOpt) (bean.Definition, error) {
+	return bean.Replace(c.Container, name, o, opts...)
+}
+
+func (c *recordContainer) Remove(name string) (bean.Definition, bool) {
+	return bean.Remove(c.Container, name)
+}
+
+func (c *recordContainer) FindByType(t reflect.Type) []bean.Definition {
+	return bean.FindByType(c.Container, t)
+}
+
+func (c *recordContainer) FindAssignable(t reflect.Type) []bean.Definition {
+	return bean.FindAssignable(c.Container, t)
+}
+
+// 按依赖关系对对象定义进行拓扑排序：被依赖的对象排在依赖方之前，无依赖关系时保持defs中的顺序（即order及注册顺序）
+// 返回排序后的对象定义以及检测到的循环依赖（每个循环为完整的依赖路径，首尾相同）
+func (r *dependencyRecorder) sort(defs []bean.Definition) ([]bean.Definition, [][]string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	index := make(map[bean.Definition]int, len(defs))
+	for i, d := range defs {
+		index[d] = i
+	}
+	// 依赖方 -> 被依赖方
+	deps := make(map[bean.Definition][]bean.Definition, len(defs))
+	// 被依赖方 -> 依赖方
+	dependents := make(map[bean.Definition][]bean.Definition, len(defs))
+	waiting := make(map[bean.Definition]int, len(defs))
+	seen := map[[2]bean.Definition]bool{}
+	for _, e := range r.edges {
+		if e.from == e.to {
+			continue
+		}
+		if _, ok := index[e.from]; !ok {
+			continue
+		}
+		if _, ok := index[e.to]; !ok {
+			continue
+		}
+		key := [2]bean.Definition{e.from, e.to}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deps[e.from] = append(deps[e.from], e.to)
+		dependents[e.to] = append(dependents[e.to], e.from)
+		waiting[e.from]++
+	}
+
+	// Kahn算法：依赖已全部完成的对象按defs中的顺序出队
+	ready := &indexQueue{}
+	for i, d := range defs {
+		if waiting[d] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+	var cycles [][]string
+	ret := make([]bean.Definition, 0, len(defs))
+	done := make([]bool, len(defs))
+	// 未完成的对象中顺序最靠前的位置
+	first := 0
+	for len(ret) < len(defs) {
+		next := -1
+		for ready.Len() > 0 {
+			if i := heap.Pop(ready).(int); !done[i] {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			// 存在循环依赖，报告循环后选择循环中顺序最靠前的对象继续
+			for done[first] {
+				first++
+			}
+			next = first
+			c := r.findCycle(defs[first], deps, index, done)
+			if c != nil {
+				next = index[c[0]]
+				names := make([]string, 0, len(c))
+				for _, v := range c {
+					names = append(names, r.nameOf(v))
+					if index[v] < next {
+						next = index[v]
+					}
+				}
+				cycles = append(cycles, names)
+			}
+		}
+		d := defs[next]
+		done[next] = true
+		waiting[d] = 0
+		ret = append(ret, d)
+		for _, from := range dependents[d] {
+			if waiting[from] > 0 {
+				waiting[from]--
+				if waiting[from] == 0 {
+					heap.Push(ready, index[from])
+				}
+			}
+		}
+	}
+	return ret, cycles
+}
+
+// 按defs中的位置出队的优先队列
+type indexQueue []int
+
+func (q indexQueue) Len() int {
+	return len(q)
+}
+
+func (q indexQueue) Less(i, j int) bool {
+	return q[i] < q[j]
+}
+
+func (q indexQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *indexQueue) Push(x interface{}) {
+	*q = append(*q, x.(int))
+}
+
+func (q *indexQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
+
+// 从start开始在未完成的对象中查找循环依赖路径
+func (r *dependencyRecorder) findCycle(start bean.Definition, deps map[bean.Definition][]bean.Definition,
+	index map[bean.Definition]int, done []bool) []bean.Definition {
+	var path []bean.Definition
+	onPath := map[bean.Definition]int{}
+	visited := map[bean.Definition]bool{}
+
+	var visit func(d bean.Definition) []bean.Definition
+	visit = func(d bean.Definition) []bean.Definition {
+		if i, ok := onPath[d]; ok {
+			return append(path[i:], d)
+		}
+		if visited[d] {
+			return nil
+		}
+		visited[d] = true
+		onPath[d] = len(path)
+		path = append(path, d)
+		for _, to := range deps[d] {
+			if done[index[to]] {
+				continue
+			}
+			if c := visit(to); c != nil {
+				return c
+			}
+		}
+		path = path[:len(path)-1]
+		delete(onPath, d)
+		return nil
+	}
+
+	return visit(start)
+}
+
+func (r *dependencyRecorder) name(d bean.Definition) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.nameOf(d)
+}
+
+func (r *dependencyRecorder) nameOf(d bean.Definition) string {
+	if n, ok := r.nodeMap[d]; ok {
+		return n.name
+	}
+	return d.Name()
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"github.com/xfali/neve-core/bean"
+)
+
+// 创建子ApplicationContext
+// 子ApplicationContext的容器在获取及自动注入时会使用父ApplicationContext中的对象，
+// 子ApplicationContext拥有独立的生命周期（Start/Close），关闭时不会销毁父ApplicationContext中的对象
+// 父ApplicationContext在opts之后配置，详情查看OptSetParent
+func NewChildApplicationContext(parent ApplicationContext, opts ...Opt) *defaultApplicationContext {
+	return NewDefaultApplicationContext(append(append([]Opt{}, opts...), OptSetParent(parent))...)
+}
+
+// 配置父ApplicationContext，应在OptSetContainer之后配置：
+// 当前容器已经以父ApplicationContext的容器为父容器（bean.OptContainerParent）时保留当前容器，
+// 否则使用以父容器创建的新容器替换当前容器
+func OptSetParent(parent ApplicationContext) Opt {
+	return func(context *defaultApplicationContext) {
+		context.parent = parent
+		if p, ok := parent.(*defaultApplicationContext); ok {
+			if c, ok := context.container.(bean.HierarchicalContainer); ok && c.Parent() == p.container {
+				return
+			}
+			context.container = bean.NewContainer(bean.OptContainerParent(p.container))
+		} else {
+			context.logger.Warnln("Parent ApplicationContext not support share container. ")
+		}
+	}
+}
+
+// 配置将事件传播到父ApplicationContext（ApplicationContext自身的生命周期事件除外）
+func OptPropagateEvent() Opt {
+	return func(context *defaultApplicationContext) {
+		context.propagateEvent = true
+	}
+}
+
+func (ctx *defaultApplicationContext) GetParent() ApplicationContext {
+	return ctx.parent
+}
+
+// 仅遍历当前容器中的对象定义，ApplicationContext的生命周期只管理当前容器中的对象
+func (ctx *defaultApplicationContext) scanLocal(f func(key string, value bean.Definition) bool) {
+	if c, ok := ctx.container.(bean.HierarchicalContainer); ok {
+		c.ScanLocal(f)
+		return
+	}
+	ctx.container.Scan(f)
+}
+
+// 将子ApplicationContext的事件传播到父ApplicationContext
+type parentEventListener struct {
+	ctx    *defaultApplicationContext
+	parent ApplicationContext
+}
+
+func (l *parentEventListener) OnApplicationEvent(e ApplicationEvent) {
+	if v, ok := e.(interface{ GetAppContext() ApplicationContext }); ok {
+		if v.GetAppContext() == ApplicationContext(l.ctx) {
+			return
+		}
+	}
+	err := l.parent.PublishEvent(e)
+	if err != nil {
+		l.ctx.logger.Errorln("Propagate event to parent failed: ", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"strconv"
+	"sync"
+)
+
+const (
+	ConfigKeyInjectWorkers = "neve.inject.workers"
+	defaultInjectWorkers   = 1
+)
+
+func parseWorkers(v string) int {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return defaultInjectWorkers
+	}
+	return n
+}
+
+// 使用workers个任务并行处理对象定义，workers为1时按defs的顺序串行执行
+// 每个对象定义在以下对象定义处理完成之后才开始处理（仅限defs中排在其之前的对象定义，保证不会死锁）：
+// 1、deps中记录的被依赖的对象定义
+// 2、order更小的对象定义
+// 任务中的panic在所有任务结束之后重新抛出
+func (ctx *defaultApplicationContext) runTasks(defs []bean.Definition, deps map[bean.Definition][]bean.Definition, task func(d bean.Definition)) {
+	if ctx.workers <= 1 || len(defs) <= 1 {
+		for _, d := range defs {
+			task(d)
+		}
+		return
+	}
+
+	index := make(map[bean.Definition]int, len(defs))
+	orders := make([]int, len(defs))
+	done := make([]chan struct{}, len(defs))
+	for i, d := range defs {
+		index[d] = i
+		orders[i] = ctx.graph.order(d)
+		done[i] = make(chan struct{})
+	}
+
+	sem := make(chan struct{}, ctx.workers)
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	var panicValue interface{}
+	for i, d := range defs {
+		var waits []chan struct{}
+		for _, dep := range deps[d] {
+			if j, ok := index[dep]; ok && j < i {
+				waits = append(waits, done[j])
+			}
+		}
+		for j := 0; j < i; j++ {
+			if orders[j] < orders[i] {
+				waits = append(waits, done[j])
+			}
+		}
+
+		wg.Add(1)
+		go func(d bean.Definition, waits []chan struct{}, ch chan struct{}) {
+			defer wg.Done()
+			defer close(ch)
+			for _, w := range waits {
+				<-w
+			}
+
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			defer func() {
+				if v := recover(); v != nil {
+					panicOnce.Do(func() {
+						panicValue = v
+					})
+				}
+			}()
+			task(d)
+		}(d, waits, done[i])
+	}
+	wg.Wait()
+	if panicValue != nil {
+		panic(panicValue)
+	}
+}
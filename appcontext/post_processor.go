@@ -0,0 +1,134 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"github.com/xfali/neve-core/processor"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+func (ctx *defaultApplicationContext) addPostProcessor(p processor.BeanPostProcessor) {
+	ctx.processorsLock.Lock()
+	defer ctx.processorsLock.Unlock()
+
+	ctx.postProcessors = append(ctx.postProcessors, p)
+}
+
+func (ctx *defaultApplicationContext) beanPostProcessors() []processor.BeanPostProcessor {
+	ctx.processorsLock.Lock()
+	defer ctx.processorsLock.Unlock()
+
+	ret := make([]processor.BeanPostProcessor, len(ctx.postProcessors))
+	copy(ret, ctx.postProcessors)
+	return ret
+}
+
+// 初始化对象定义，init为初始化方法（如BeanAfterSet）
+// 单例对象在init前后依次调用BeanPostProcessor，处理器返回新的对象时替换依赖方注入及获取到的对象
+func (ctx *defaultApplicationContext) initDefinition(d bean.Definition, init func() error) error {
+	pps := ctx.beanPostProcessors()
+	dd, ok := d.(bean.Decoratable)
+	if len(pps) == 0 || !ok {
+		return init()
+	}
+	v := dd.Instance()
+	if isNilValue(v) {
+		return init()
+	}
+	o := v.Interface()
+	// 处理器本身不做处理
+	if _, ok := o.(processor.BeanPostProcessor); ok {
+		return init()
+	}
+
+	name := ctx.graph.name(d)
+	cur := o
+	var err error
+	for _, p := range pps {
+		cur, err = p.BeforeInit(name, cur)
+		if err != nil {
+			return fmt.Errorf("Bean [%s] BeforeInit failed: %w ", name, err)
+		}
+	}
+	err = init()
+	if err != nil {
+		return err
+	}
+	for _, p := range pps {
+		cur, err = p.AfterInit(name, cur)
+		if err != nil {
+			return fmt.Errorf("Bean [%s] AfterInit failed: %w ", name, err)
+		}
+	}
+	if sameObject(o, cur) {
+		return nil
+	}
+	return ctx.decorate(d, dd, name, cur)
+}
+
+// 使用处理器返回的对象替换原对象，并重新注入已经注入了原对象的依赖方
+func (ctx *defaultApplicationContext) decorate(d bean.Definition, dd bean.Decoratable, name string, o interface{}) error {
+	if o == nil {
+		return fmt.Errorf("Bean [%s] post processor return nil. ", name)
+	}
+	v := reflect.ValueOf(o)
+	if !v.Type().AssignableTo(d.Type()) {
+		return fmt.Errorf("Bean [%s] post processor return type %s cannot assign to %s. ",
+			name, reflection.GetTypeName(v.Type()), reflection.GetTypeName(d.Type()))
+	}
+	dd.Decorate(v)
+	// 自动注入slice、map时缓存了原对象
+	injector.RemoveCaches(ctx.container, d)
+	for _, dep := range ctx.graph.dependents(d) {
+		ctx.reinject(dep)
+	}
+	return nil
+}
+
+func isNilValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// 判断处理器是否返回了原对象
+func sameObject(a, b interface{}) bool {
+	if b == nil {
+		return false
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return va.Pointer() == vb.Pointer()
+	}
+	if va.Type().Comparable() {
+		return a == b
+	}
+	return true
+}
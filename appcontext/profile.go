@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"github.com/xfali/fig"
+	"os"
+	"strings"
+)
+
+const (
+	// 激活的profile配置，多个profile使用逗号分隔，如：dev,local
+	ConfigKeyProfilesActive = "neve.profiles.active"
+	// 激活的profile环境变量，优先级高于配置
+	EnvNameProfilesActive = "NEVE_PROFILES_ACTIVE"
+)
+
+// 获得激活的profile，环境变量NEVE_PROFILES_ACTIVE优先于配置neve.profiles.active
+func ActiveProfiles(conf fig.Properties) []string {
+	active, ok := os.LookupEnv(EnvNameProfilesActive)
+	if !ok && conf != nil {
+		active = conf.Get(ConfigKeyProfilesActive, "")
+	}
+	return splitProfiles(active)
+}
+
+func splitProfiles(s string) []string {
+	var ret []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// 判断bean配置的profile是否与激活的profile匹配，满足任意一个即匹配
+// 以"!"开头的profile表示该profile未激活时匹配
+func matchProfiles(active, profiles []string) bool {
+	if len(profiles) == 0 {
+		return true
+	}
+	for _, p := range profiles {
+		if strings.HasPrefix(p, "!") {
+			if !containsProfile(active, p[1:]) {
+				return true
+			}
+		} else if containsProfile(active, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsProfile(active []string, profile string) bool {
+	for _, v := range active {
+		if v == profile {
+			return true
+		}
+	}
+	return false
+}
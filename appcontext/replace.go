@@ -0,0 +1,127 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+)
+
+func (ctx *defaultApplicationContext) ReplaceBean(name string, o interface{}, opts ...bean.RegisterOpt) error {
+	if ctx.isInitializing() {
+		return errors.New("Initializing, cannot replace bean. ")
+	}
+	if o == nil {
+		return errors.New("Bean is nil. ")
+	}
+	r, err := ctx.prepareRegister(o, opts)
+	if r == nil || err != nil {
+		return err
+	}
+	// 配置类的bean方法注册为独立的对象，无法随配置类替换
+	if isConfiguration(r.o, r.regOpts) {
+		return fmt.Errorf("Bean %s cannot be replaced with configuration. ", name)
+	}
+	if cur, ok := ctx.container.GetDefinition(name); ok && ctx.configurationOf(cur) != nil {
+		return fmt.Errorf("Bean %s is configuration, cannot be replaced. ", name)
+	}
+	// 新对象代替原对象判断注册条件，不满足条件时保留原对象
+	if cur, ok := ctx.container.GetDefinition(name); ok && !ctx.matchRegisterConditions(name, r.regOpts, cur) {
+		return nil
+	}
+	o = r.o
+	regOpts := r.regOpts
+	old, err := bean.Replace(ctx.container, name, o, r.opts...)
+	if err != nil {
+		return err
+	}
+	// 自动注入slice、map时缓存了原对象
+	injector.RemoveCaches(ctx.container, old)
+	d, ok := ctx.container.GetDefinition(name)
+	if !ok {
+		return fmt.Errorf("Bean %s not found after replaced. ", name)
+	}
+	dependents := ctx.graph.dependents(old)
+	ctx.graph.replace(old, d, regOpts.order, regOpts.orderSet)
+	ctx.completeRegister(r, d)
+
+	// 未启动时由Start统一初始化
+	if !ctx.isInitialized() {
+		return ctx.postRegister(o, r.rc, regOpts.lazy)
+	}
+
+	ctx.initRuntimeBean(o, d)
+
+	// 重新注入依赖原对象的对象
+	for _, dep := range dependents {
+		ctx.reinject(dep)
+	}
+
+	ctx.removeInitialized(old)
+	err = old.Destroy()
+	if err != nil {
+		ctx.logger.Errorln(err)
+	}
+
+	if !ctx.disableEvent {
+		return ctx.PublishEvent(NewBeanReplacedEvent(ctx, name, old, d))
+	}
+	return nil
+}
+
+func (ctx *defaultApplicationContext) reinject(d bean.Definition) {
+	if ctx.disableInject {
+		return
+	}
+	if !d.IsObject() {
+		// 构造方法创建的对象无法重新注入
+		ctx.logger.Warnf("Bean [%s] is created by function, cannot be re-injected\n", ctx.graph.name(d))
+		return
+	}
+	ctx.injectObject(d, d.Interface(), nil)
+}
+
+func (ctx *defaultApplicationContext) RemoveBean(name string) error {
+	if ctx.isInitializing() {
+		return errors.New("Initializing, cannot remove bean. ")
+	}
+	d, ok := bean.Remove(ctx.container, name)
+	if !ok {
+		return fmt.Errorf("Bean %s not found. ", name)
+	}
+	injector.RemoveCaches(ctx.container, d)
+	for _, dep := range ctx.graph.dependents(d) {
+		ctx.logger.Warnf("Removed bean [%s] is still referenced by [%s]\n", name, ctx.graph.name(dep))
+	}
+	ctx.graph.remove(d)
+
+	if !ctx.isInitialized() {
+		return nil
+	}
+	ctx.removeInitialized(d)
+	err := d.Destroy()
+	if err != nil {
+		ctx.logger.Errorln(err)
+	}
+
+	if !ctx.disableEvent {
+		return ctx.PublishEvent(NewBeanRemovedEvent(ctx, name, d))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"context"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/errors"
+	"reflect"
+	"sync"
+)
+
+// Bean作用域，绑定context.Context的生命周期
+// 作用域为bean.Context的对象在同一个BeanScope内只创建一次，并在作用域结束时（调用Close或者context Done）销毁
+// 其他作用域的对象与ApplicationContext.GetBean行为一致
+// 注意：构造方法的参数仍然从ApplicationContext注入，不会使用作用域内缓存的对象
+type BeanScope interface {
+	// 获得作用域绑定的context
+	Context() context.Context
+
+	// 根据名称获得对象，如果容器中包含该对象，则返回对象和true否则返回nil和false
+	// 构造方法返回错误时记录错误并返回nil和false
+	GetBean(name string) (interface{}, bool)
+
+	// 结束作用域，按创建的相反顺序销毁作用域内创建的对象
+	Close() error
+}
+
+type scopedInstance struct {
+	def bean.Definition
+	v   reflect.Value
+}
+
+type defaultBeanScope struct {
+	appCtx *defaultApplicationContext
+	ctx    context.Context
+
+	instances map[bean.Definition]reflect.Value
+	ordered   []scopedInstance
+	closed    bool
+	locker    sync.Mutex
+
+	closeChan chan struct{}
+	closeOnce sync.Once
+}
+
+func newBeanScope(appCtx *defaultApplicationContext, ctx context.Context) *defaultBeanScope {
+	ret := &defaultBeanScope{
+		appCtx:    appCtx,
+		ctx:       ctx,
+		instances: map[bean.Definition]reflect.Value{},
+		closeChan: make(chan struct{}),
+	}
+	if ctx.Done() != nil {
+		go ret.waitDone()
+	}
+	return ret
+}
+
+func (s *defaultBeanScope) waitDone() {
+	select {
+	case <-s.ctx.Done():
+		err := s.Close()
+		if err != nil {
+			s.appCtx.logger.Errorln(err)
+		}
+	case <-s.closeChan:
+	}
+}
+
+func (s *defaultBeanScope) Context() context.Context {
+	return s.ctx
+}
+
+func (s *defaultBeanScope) GetBean(name string) (interface{}, bool) {
+	d, ok := s.appCtx.container.GetDefinition(name)
+	if !ok {
+		return nil, false
+	}
+	if bean.ScopeOf(d) != bean.Context {
+		return s.appCtx.GetBean(name)
+	}
+
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.closed {
+		return nil, false
+	}
+	if v, ok := s.instances[d]; ok {
+		return v.Interface(), true
+	}
+	v, err := definitionValue(d)
+	if err != nil {
+		s.appCtx.logger.Errorln(err)
+		return nil, false
+	}
+	err = s.appCtx.initInstance(d, v)
+	if err != nil {
+		s.appCtx.logger.Errorln(err)
+	}
+	s.instances[d] = v
+	s.ordered = append(s.ordered, scopedInstance{def: d, v: v})
+	return v.Interface(), true
+}
+
+func (s *defaultBeanScope) Close() (err error) {
+	s.closeOnce.Do(func() {
+		close(s.closeChan)
+
+		s.locker.Lock()
+		s.closed = true
+		ordered := s.ordered
+		s.ordered = nil
+		s.instances = nil
+		s.locker.Unlock()
+
+		var errs errors.Errors
+		for i := len(ordered) - 1; i >= 0; i-- {
+			if l, ok := ordered[i].def.(bean.InstanceLifecycle); ok {
+				dErr := l.DestroyInstance(ordered[i].v)
+				if dErr != nil {
+					errs.AddError(dErr)
+				}
+			}
+		}
+		if !errs.Empty() {
+			err = errs
+		}
+	})
+	return
+}
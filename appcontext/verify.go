@@ -0,0 +1,172 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appcontext
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"github.com/xfali/neve-core/reflection"
+	"io"
+	"strings"
+)
+
+// 依赖检查中的单个问题
+type VerifyIssue struct {
+	// 依赖方（被注入的bean）名称
+	Bean string
+	// 检查结果
+	Status injector.VerifyStatus
+	// 注入点
+	Point injector.InjectPoint
+	// 注入的类型名称
+	Type string
+	// 注入名称（包含注入选项），自动注入时为空
+	Name string
+	// 无法注入的原因
+	Message string
+}
+
+// 依赖检查报告
+type VerifyReport struct {
+	// 检查的注入点数量
+	Checked int
+	// 找不到依赖对象的必须注入点
+	Unresolved []VerifyIssue
+	// 匹配到多个候选对象且无法确定的注入点
+	Ambiguous []VerifyIssue
+	// 找不到依赖对象的可选注入点（如omiterror），不影响检查结果
+	OptionalMissing []VerifyIssue
+	// 构造方法之间的循环依赖，详情查看CircularDependencyError
+	Cycles [][]BeanEdge
+	// 经过字段依赖的循环依赖，字段在对象创建之后注入，不影响检查结果
+	FieldCycles [][]BeanEdge
+}
+
+// 是否通过检查：不存在无法注入、无法确定的注入点以及循环依赖
+func (r *VerifyReport) OK() bool {
+	return len(r.Unresolved) == 0 && len(r.Ambiguous) == 0 && len(r.Cycles) == 0
+}
+
+func (r *VerifyReport) add(bean string, results []injector.VerifyResult) {
+	for _, v := range results {
+		r.Checked++
+		if v.Status == injector.VerifyResolved {
+			continue
+		}
+		issue := VerifyIssue{
+			Bean:   bean,
+			Status: v.Status,
+			Point:  v.Point,
+			Type:   reflection.GetTypeName(v.Type),
+			Name:   v.Name,
+		}
+		if v.Err != nil {
+			issue.Message = strings.TrimSpace(v.Err.Error())
+		}
+		switch v.Status {
+		case injector.VerifyUnresolved:
+			r.Unresolved = append(r.Unresolved, issue)
+		case injector.VerifyAmbiguous:
+			r.Ambiguous = append(r.Ambiguous, issue)
+		case injector.VerifyOptionalMissing:
+			r.OptionalMissing = append(r.OptionalMissing, issue)
+		}
+	}
+}
+
+// 以文本格式输出检查报告
+func (r *VerifyReport) Write(w io.Writer) error {
+	buf := bytes.Buffer{}
+	result := "OK"
+	if !r.OK() {
+		result = "FAILED"
+	}
+	buf.WriteString(fmt.Sprintf("Wiring verification %s: %d checked, %d unresolved, %d ambiguous, %d optional missing, %d cycle(s)\n",
+		result, r.Checked, len(r.Unresolved), len(r.Ambiguous), len(r.OptionalMissing), len(r.Cycles)))
+	for _, issues := range [][]VerifyIssue{r.Unresolved, r.Ambiguous, r.OptionalMissing} {
+		for _, v := range issues {
+			buf.WriteString(fmt.Sprintf("  [%s] %s [%s] %s", v.Status, v.Bean, v.Point.String(), v.Type))
+			if v.Name != "" {
+				buf.WriteString(fmt.Sprintf(" name: %s", v.Name))
+			}
+			buf.WriteString(fmt.Sprintf(": %s\n", v.Message))
+		}
+	}
+	if len(r.Cycles) > 0 {
+		buf.WriteString(strings.TrimSpace((&CircularDependencyError{Cycles: r.Cycles}).Error()))
+		buf.WriteString("\n")
+	}
+	for _, c := range r.FieldCycles {
+		buf.WriteString(fmt.Sprintf("  [FIELD_CYCLE] %s\n", cyclePath(c)))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// 返回文本格式的检查报告
+func (r *VerifyReport) String() string {
+	buf := bytes.Buffer{}
+	_ = r.Write(&buf)
+	return buf.String()
+}
+
+func (ctx *defaultApplicationContext) Verify() (*VerifyReport, error) {
+	v, ok := ctx.injector.(injector.Verifier)
+	if !ok {
+		return nil, errors.New("Injector does not support verify. ")
+	}
+	// 与启动时一致先判断注册条件，检查时不修改容器：不满足条件的bean仅在检查时隐藏
+	view := ctx.previewConditions()
+	var defs []bean.Definition
+	for _, d := range ctx.definitions() {
+		if view.contains(d) {
+			defs = append(defs, d)
+		}
+	}
+
+	report := &VerifyReport{}
+	for _, d := range defs {
+		name := ctx.graph.name(d)
+		if d.IsObject() {
+			report.add(name, v.VerifyFields(view, d.Interface()))
+			if f, ok := d.Interface().(injector.InjectFunction); ok {
+				results, err := injector.VerifyInjectFunction(v, view, f)
+				if err != nil {
+					return nil, fmt.Errorf("Verify bean [%s] inject function failed: %w ", name, err)
+				}
+				report.add(name, results)
+			}
+		} else if f := ctx.graph.factory(d); f != nil {
+			report.add(name, injector.VerifyFactory(v, view, f))
+		}
+	}
+
+	fieldCycles, err := ctx.checkCycles(view, defs)
+	report.FieldCycles = fieldCycles
+	var cerr *CircularDependencyError
+	if errors.As(err, &cerr) {
+		report.Cycles = cerr.Cycles
+	}
+	if !report.OK() {
+		return report, fmt.Errorf("Verify failed: %d unresolved, %d ambiguous, %d cycle(s) ",
+			len(report.Unresolved), len(report.Ambiguous), len(report.Cycles))
+	}
+	return report, nil
+}
@@ -58,6 +58,14 @@ type Application interface {
 	Stop()
 }
 
+// VerifiableApplication 支持依赖检查的Application
+type VerifiableApplication interface {
+	Application
+
+	// Verify 检查依赖，不会创建对象或者调用生命周期方法，详情查看appcontext.InspectableApplicationContext.Verify
+	Verify() (*appcontext.VerifyReport, error)
+}
+
 const (
 	QuitSleepTime = 3 * time.Second
 )
@@ -116,6 +124,12 @@ func NewFileConfigApplication(configPath string, opts ...Opt) *FileConfigApplica
 		xlog.Errorln("load config file failed: ", err)
 		return nil
 	}
+	// 合并激活的profile配置：application-{profile}.yaml
+	prop, err = LoadProfileProperties(configPath, prop)
+	if err != nil {
+		xlog.Errorln("load profile config file failed: ", err)
+		return nil
+	}
 	return NewApplication(prop, opts...)
 }
 
@@ -131,6 +145,10 @@ func (app *FileConfigApplication) AddListeners(listeners ...interface{}) {
 	app.ctx.AddListeners(listeners...)
 }
 
+func (app *FileConfigApplication) Verify() (*appcontext.VerifyReport, error) {
+	return appcontext.Verify(app.ctx)
+}
+
 func (app *FileConfigApplication) Run() error {
 	return app.RunWithContext(context.Background())
 }
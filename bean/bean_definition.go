@@ -19,6 +19,7 @@ package bean
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type Classifier interface {
@@ -55,6 +56,99 @@ type Definition interface {
 	Classify(classifier Classifier) (bool, error)
 }
 
+// 以下为对象定义的可选接口，内置的对象定义均已实现。
+// 自定义的对象定义（RegisterBeanDefinitionCreator）可以按需实现，未实现时使用默认值，
+// 应使用ScopeOf、IsPrimary、QualifiersOf、IsLazy、AttributeOf、AttributesOf、LabelsOf获取
+
+// 支持作用域的对象定义，未实现时为Singleton
+type ScopedDefinition interface {
+	// 作用域
+	Scope() Scope
+}
+
+// 支持primary及限定符的对象定义，未实现时非primary且没有限定符
+type QualifiedDefinition interface {
+	// 自动注入匹配多个对象时是否优先选择
+	IsPrimary() bool
+
+	// 限定符，用于在自动注入时从多个对象中选择
+	Qualifiers() []string
+}
+
+// 支持延迟初始化的对象定义，未实现时不延迟初始化
+type LazyDefinition interface {
+	// 是否延迟初始化
+	IsLazy() bool
+}
+
+// 支持属性及标签的对象定义，未实现时没有属性及标签
+type AttributedDefinition interface {
+	// 获得注册时配置的属性，详情查看SetAttribute
+	Attribute(key string) (interface{}, bool)
+
+	// 获得注册时配置的所有属性，返回值不可修改
+	Attributes() map[string]interface{}
+
+	// 获得注册时配置的标签，详情查看SetLabels，返回值不可修改
+	Labels() map[string]string
+}
+
+// 获得对象定义的作用域，未实现ScopedDefinition时返回Singleton
+func ScopeOf(d Definition) Scope {
+	if v, ok := d.(ScopedDefinition); ok {
+		return v.Scope()
+	}
+	return Singleton
+}
+
+// 对象定义是否为primary，未实现QualifiedDefinition时返回false
+func IsPrimary(d Definition) bool {
+	if v, ok := d.(QualifiedDefinition); ok {
+		return v.IsPrimary()
+	}
+	return false
+}
+
+// 获得对象定义的限定符，未实现QualifiedDefinition时返回nil
+func QualifiersOf(d Definition) []string {
+	if v, ok := d.(QualifiedDefinition); ok {
+		return v.Qualifiers()
+	}
+	return nil
+}
+
+// 对象定义是否延迟初始化，未实现LazyDefinition时返回false
+func IsLazy(d Definition) bool {
+	if v, ok := d.(LazyDefinition); ok {
+		return v.IsLazy()
+	}
+	return false
+}
+
+// 获得对象定义的属性，未实现AttributedDefinition时返回false
+func AttributeOf(d Definition, key string) (interface{}, bool) {
+	if v, ok := d.(AttributedDefinition); ok {
+		return v.Attribute(key)
+	}
+	return nil, false
+}
+
+// 获得对象定义的所有属性，未实现AttributedDefinition时返回nil
+func AttributesOf(d Definition) map[string]interface{} {
+	if v, ok := d.(AttributedDefinition); ok {
+		return v.Attributes()
+	}
+	return nil
+}
+
+// 获得对象定义的标签，未实现AttributedDefinition时返回nil
+func LabelsOf(d Definition) map[string]string {
+	if v, ok := d.(AttributedDefinition); ok {
+		return v.Labels()
+	}
+	return nil
+}
+
 type DefinitionCreator func(o interface{}) (Definition, error)
 
 var (
@@ -66,17 +160,64 @@ var (
 		reflect.Func:  newFunctionExDefinition,
 		reflect.Slice: newSliceDefinition,
 		reflect.Map:   newMapDefinition,
+		// 基础类型及结构体的值
+		reflect.Bool:       newValueDefinition,
+		reflect.String:     newValueDefinition,
+		reflect.Struct:     newValueDefinition,
+		reflect.Int:        newValueDefinition,
+		reflect.Int8:       newValueDefinition,
+		reflect.Int16:      newValueDefinition,
+		reflect.Int32:      newValueDefinition,
+		reflect.Int64:      newValueDefinition,
+		reflect.Uint:       newValueDefinition,
+		reflect.Uint8:      newValueDefinition,
+		reflect.Uint16:     newValueDefinition,
+		reflect.Uint32:     newValueDefinition,
+		reflect.Uint64:     newValueDefinition,
+		reflect.Float32:    newValueDefinition,
+		reflect.Float64:    newValueDefinition,
+		reflect.Complex64:  newValueDefinition,
+		reflect.Complex128: newValueDefinition,
 	}
 )
 
 // 注册BeanDefinition创建器，使其能处理更多类型。
-// 默认支持Pointer、Function
+// 默认支持Pointer、Function、Slice、Map以及基础类型和结构体的值
 func RegisterBeanDefinitionCreator(kind reflect.Kind, creator DefinitionCreator) {
 	if creator != nil {
 		beanDefinitionCreators[kind] = creator
 	}
 }
 
+// 判断对象定义是否包含限定符
+func HasQualifier(d Definition, qualifier string) bool {
+	for _, q := range QualifiersOf(d) {
+		if q == qualifier {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断对象定义是否包含所有标签，标签格式为"key=value"（值必须相同）或"key"（仅判断是否包含该key）
+func MatchLabels(d Definition, labels ...string) bool {
+	dl := LabelsOf(d)
+	for _, l := range labels {
+		k, v := parseLabel(l)
+		if k == "" {
+			continue
+		}
+		lv, ok := dl[k]
+		if !ok {
+			return false
+		}
+		if strings.Contains(l, "=") && lv != v {
+			return false
+		}
+	}
+	return true
+}
+
 func CreateBeanDefinition(o interface{}) (Definition, error) {
 	if v, ok := o.(CustomBeanFactory); ok {
 		return newCustomMethodBeanDefinition(v)
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+// bean注册条件，在ApplicationContext启动时（注入之前）按注册顺序判断，不满足条件的bean将从容器中移除
+type Condition interface {
+	// 判断条件是否满足
+	// conf: 应用配置，container: 对象容器，self: 待判断的bean的对象定义
+	Matches(conf fig.Properties, container Container, self Definition) bool
+
+	// 条件描述，用于输出日志
+	String() string
+}
+
+type propertyCondition struct {
+	key   string
+	value string
+}
+
+func (c *propertyCondition) Matches(conf fig.Properties, container Container, self Definition) bool {
+	if conf == nil {
+		return false
+	}
+	return conf.Get(c.key, "") == c.value
+}
+
+func (c *propertyCondition) String() string {
+	return fmt.Sprintf("OnProperty(%s=%s)", c.key, c.value)
+}
+
+type beanCondition struct {
+	name    string
+	t       reflect.Type
+	missing bool
+}
+
+func newBeanCondition(o interface{}, missing bool) *beanCondition {
+	ret := &beanCondition{
+		missing: missing,
+	}
+	switch v := o.(type) {
+	case string:
+		ret.name = v
+	case reflect.Type:
+		ret.t = v
+	default:
+		t := reflect.TypeOf(o)
+		// 支持使用接口的空指针表示接口类型，如：(*io.Closer)(nil)
+		if t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+			t = t.Elem()
+		}
+		ret.t = t
+	}
+	return ret
+}
+
+func (c *beanCondition) exists(container Container, self Definition) bool {
+	if c.name != "" {
+		d, ok := container.GetDefinition(c.name)
+		return ok && d != self
+	}
+	if c.t == nil {
+		return false
+	}
+	found := false
+	container.Scan(func(key string, value Definition) bool {
+		if value != self && value.Type().AssignableTo(c.t) {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func (c *beanCondition) Matches(conf fig.Properties, container Container, self Definition) bool {
+	return c.exists(container, self) != c.missing
+}
+
+func (c *beanCondition) String() string {
+	target := c.name
+	if target == "" && c.t != nil {
+		target = reflection.GetTypeName(c.t)
+	}
+	if c.missing {
+		return fmt.Sprintf("OnMissingBean(%s)", target)
+	}
+	return fmt.Sprintf("OnBean(%s)", target)
+}
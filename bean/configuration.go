@@ -0,0 +1,40 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+// 配置类提供的bean方法
+type BeanMethod struct {
+	// 方法名，方法必须为公开方法，返回T或者(T, error)，参数在创建实例时自动注入
+	Method string
+
+	// bean名称，为空时使用返回值类型的默认名称
+	Name string
+
+	// 参数的注入名称，规则同CustomBeanFactory.InjectNames
+	InjectNames []string
+
+	// bean注册配置，未配置作用域时默认为Singleton
+	Opts []RegisterOpt
+}
+
+// 配置类：注册时将方法返回的对象注册为bean，实现该接口的对象注册时自动作为配置类处理
+// 未实现该接口且使用SetConfiguration注册的对象，名称以Bean结尾的公开方法作为bean方法
+// 配置类本身作为普通bean注册，在调用bean方法之前完成注入及分类（如ValueProcessor的属性值注入）
+type BeanProvider interface {
+	// 返回提供bean的方法
+	BeanMethods() []BeanMethod
+}
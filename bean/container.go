@@ -16,6 +16,13 @@
 
 package bean
 
+import (
+	"errors"
+	"reflect"
+)
+
+var ErrContainerFrozen = errors.New("Container is frozen, cannot register new object, use dynamic registration instead. ")
+
 type Container interface {
 	// 注册对象
 	// opts添加bean注册的配置，详情查看RegisterOpt
@@ -30,6 +37,7 @@ type Container interface {
 	Get(name string) (o interface{}, ok bool)
 
 	// 根据类型获得对象，值设置到参数中
+	// o为指向接收对象的变量的指针，如：var a A; container.GetByType(&a)
 	// return：ok：如果成功为true，否则为false
 	GetByType(o interface{}) bool
 
@@ -46,3 +54,127 @@ type Container interface {
 	// 返回true则继续遍历，返回false停止遍历。
 	Scan(f func(key string, value Definition) bool)
 }
+
+// 以下为对象容器的可选接口，默认容器均已实现。
+// 自定义的容器（如appcontext.OptSetContainer）可以按需实现，应使用Alias、Replace、Remove、FindByType、FindAssignable函数调用
+
+// 支持别名的对象容器
+type AliasContainer interface {
+	Container
+
+	// 为已注册的对象添加别名，注入及获取时可使用别名，遍历时不会访问别名
+	// return：失败返回错误（对象不存在或别名已存在）
+	Alias(name, alias string) error
+}
+
+// 支持替换及移除对象的对象容器
+type MutableContainer interface {
+	Container
+
+	// 使用新的对象替换名称对应的对象定义，所有指向原对象定义的名称（如别名、注入时缓存的名称）都指向新的对象定义
+	// 注意：容器不会调用原对象的销毁方法
+	// return：old：被替换的对象定义，err：失败返回错误（如对象不存在）
+	Replace(name string, o interface{}, opts ...RegisterOpt) (old Definition, err error)
+
+	// 移除对象定义，同时移除所有指向该对象定义的名称（如别名、注入时缓存的名称）
+	// 注意：容器不会调用对象的销毁方法
+	// return：d：被移除的对象定义，ok：如果成功为true，否则为false
+	Remove(name string) (d Definition, ok bool)
+}
+
+// 支持按类型查找的对象容器
+type TypedContainer interface {
+	Container
+
+	// 查找类型与t完全一致的对象定义（每个对象定义仅返回一次，不包含别名及缓存的名称），按order及注册顺序排列
+	// 通过注册时维护的类型索引查找，无需遍历容器
+	FindByType(t reflect.Type) []Definition
+
+	// 查找类型可以赋值给t的对象定义（如实现了接口t的对象），排列规则同FindByType
+	FindAssignable(t reflect.Type) []Definition
+}
+
+// 为已注册的对象添加别名，容器未实现AliasContainer时返回错误
+func Alias(c Container, name, alias string) error {
+	if v, ok := c.(AliasContainer); ok {
+		return v.Alias(name, alias)
+	}
+	return errors.New("Container not support alias. ")
+}
+
+// 替换名称对应的对象定义，容器未实现MutableContainer时返回错误
+func Replace(c Container, name string, o interface{}, opts ...RegisterOpt) (Definition, error) {
+	if v, ok := c.(MutableContainer); ok {
+		return v.Replace(name, o, opts...)
+	}
+	return nil, errors.New("Container not support replace. ")
+}
+
+// 移除对象定义，容器未实现MutableContainer时返回false
+func Remove(c Container, name string) (Definition, bool) {
+	if v, ok := c.(MutableContainer); ok {
+		return v.Remove(name)
+	}
+	return nil, false
+}
+
+// 查找类型与t完全一致的对象定义，容器未实现TypedContainer时遍历容器查找（按遍历顺序排列）
+func FindByType(c Container, t reflect.Type) []Definition {
+	if v, ok := c.(TypedContainer); ok {
+		return v.FindByType(t)
+	}
+	return scanDefinitions(c, func(ot reflect.Type) bool {
+		return ot == t
+	})
+}
+
+// 查找类型可以赋值给t的对象定义，容器未实现TypedContainer时遍历容器查找（按遍历顺序排列）
+func FindAssignable(c Container, t reflect.Type) []Definition {
+	if v, ok := c.(TypedContainer); ok {
+		return v.FindAssignable(t)
+	}
+	return scanDefinitions(c, func(ot reflect.Type) bool {
+		return ot.AssignableTo(t)
+	})
+}
+
+func scanDefinitions(c Container, match func(t reflect.Type) bool) []Definition {
+	var ret []Definition
+	c.Scan(func(key string, value Definition) bool {
+		if match(value.Type()) {
+			ret = appendDefinitions(ret, []Definition{value})
+		}
+		return true
+	})
+	return ret
+}
+
+// 支持父容器的对象容器
+// GetDefinition、Get在当前容器中不存在时从父容器中获取，Scan同时遍历父容器中的对象（当前容器中的同名对象覆盖父容器中的对象）
+type HierarchicalContainer interface {
+	Container
+
+	// 获得父容器，不存在时返回nil
+	Parent() Container
+
+	// 仅遍历当前容器中的对象定义，不包括父容器中的对象定义
+	ScanLocal(f func(key string, value Definition) bool)
+}
+
+// 支持冻结的对象容器
+// 冻结后容器生成不可变快照（名称、顺序及类型索引），GetDefinition、Scan、FindByType等读操作无锁
+// 冻结后Register、RegisterByName返回ErrContainerFrozen，需要通过RegisterDynamic显式动态注册；
+// 动态注册、替换、移除、添加别名及对象定义（如注入时的缓存）在加锁修改后重新生成快照
+type FreezableContainer interface {
+	Container
+
+	// 冻结容器
+	Freeze()
+
+	// 是否已冻结
+	IsFrozen() bool
+
+	// 动态注册对象，冻结后仍可注册
+	// name为空时使用默认名称
+	RegisterDynamic(name string, o interface{}, opts ...RegisterOpt) error
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "reflect"
+
+// 注入时包装的构造方法使用该类型的参数接收创建链，详情查看Creation
+var CreationType = reflect.TypeOf((*Creation)(nil))
+
+// 创建链，记录当前调用路径上正在创建或者延迟初始化的对象定义。
+// 创建链沿调用路径传递（构造方法参数的注入、延迟初始化时的注入），用于：
+// 1、构造方法创建对象的过程中再次获取同一个对象定义的值时，判定为循环依赖
+// 2、延迟初始化的过程中再次获取同一个单例时（字段的循环依赖），返回正在初始化的对象而不是等待初始化完成
+// 为nil时表示不在创建过程中
+type Creation struct {
+	parent *Creation
+	d      Definition
+	// 正在延迟初始化的对象，调用构造方法时为无效值
+	v reflect.Value
+}
+
+// 返回在创建链末尾添加对象定义d后的创建链，v为正在初始化的对象
+func (c *Creation) with(d Definition, v reflect.Value) *Creation {
+	return &Creation{
+		parent: c,
+		d:      d,
+		v:      v,
+	}
+}
+
+// 查找创建链中的对象定义d，返回其正在初始化的对象（调用构造方法时为无效值）
+func (c *Creation) lookup(d Definition) (reflect.Value, bool) {
+	for cur := c; cur != nil; cur = cur.parent {
+		if cur.d == d {
+			return cur.v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// 支持沿创建链获取值的对象定义，未实现时使用Value
+type CreationValuer interface {
+	// 获得值，chain为当前调用路径上的创建链
+	ValueIn(chain *Creation) reflect.Value
+}
+
+// 获得对象定义的值，未实现CreationValuer时调用Value
+func ValueIn(d Definition, chain *Creation) reflect.Value {
+	if v, ok := d.(CreationValuer); ok {
+		return v.ValueIn(chain)
+	}
+	return d.Value()
+}
@@ -28,7 +28,7 @@ import (
 type CustomBeanFactory interface {
 	// 返回或者创建bean的方法
 	// 该方法可能包含一个或者多个参数，参数会在实例化时自动注入
-	// 该方法只能有一个返回值，返回的值将被注入到依赖该类型值的对象中
+	// 该方法返回T或者(T, error)，返回的值将被注入到依赖该类型值的对象中，返回的错误将导致启动失败
 	BeanFactory() interface{}
 
 	// BeanFactory返回创建bean方法如果带参数，且参数需要指定注入名称时将根据InjectNames返回的名称列表进行匹配
@@ -168,7 +168,7 @@ func (o customBeanFactoryOpts) PreDestroy(methodName string) beanFactoryOpt {
 var CustomBeanFactoryOpts customBeanFactoryOpts
 
 type customMethodBeanDefinition struct {
-	functionExDefinition
+	*functionExDefinition
 
 	lifeCycleFuncs map[LifeCycle]string
 }
@@ -179,7 +179,7 @@ func newCustomMethodBeanDefinition(b CustomBeanFactory) (Definition, error) {
 		return nil, err
 	}
 	ret := &customMethodBeanDefinition{
-		functionExDefinition: *d.(*functionExDefinition),
+		functionExDefinition: d.(*functionExDefinition),
 		lifeCycleFuncs:       b.BeanLifeCycleMethodNames(),
 	}
 
@@ -235,27 +235,9 @@ func (d *customMethodBeanDefinition) AfterSet() error {
 		defer d.instanceLock.RUnlock()
 		var errs errors.Errors
 		for _, i := range d.instances.MapKeys() {
-			if i.IsValid() && !i.IsNil() {
-				name := d.lifeCycleFuncs[PreAfterSet]
-				if name != "" {
-					err := d.callByName(i, name)
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
-				if d.t.Implements(InitializingType) {
-					err := i.Interface().(Initializing).BeanAfterSet()
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
-				name = d.lifeCycleFuncs[PostAfterSet]
-				if name != "" {
-					err := d.callByName(i, name)
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
+			err := d.afterSetInstance(i)
+			if err != nil {
+				errs.AddError(err)
 			}
 		}
 		if errs.Empty() {
@@ -268,33 +250,16 @@ func (d *customMethodBeanDefinition) AfterSet() error {
 
 func (d *customMethodBeanDefinition) Destroy() error {
 	if atomic.CompareAndSwapInt32(&d.destroyOnce, 0, 1) {
-		d.instanceLock.RLock()
-		defer d.instanceLock.RUnlock()
-		var errs errors.Errors
-		for _, i := range d.instances.MapKeys() {
-			if i.IsValid() && !i.IsNil() {
-				name := d.lifeCycleFuncs[PreDestroy]
-				if name != "" {
-					err := d.callByName(i, name)
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
+		d.instanceLock.Lock()
+		instances := d.instances.MapKeys()
+		d.instances = reflect.MakeMap(d.instances.Type())
+		d.instanceLock.Unlock()
 
-				if d.t.Implements(DisposableType) {
-					err := i.Interface().(Disposable).BeanDestroy()
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
-
-				name = d.lifeCycleFuncs[PostAfterSet]
-				if name != "" {
-					err := d.callByName(i, name)
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
+		var errs errors.Errors
+		for _, i := range instances {
+			err := d.destroyInstance(i)
+			if err != nil {
+				errs.AddError(err)
 			}
 		}
 		if errs.Empty() {
@@ -305,6 +270,81 @@ func (d *customMethodBeanDefinition) Destroy() error {
 	return nil
 }
 
+func (d *customMethodBeanDefinition) AfterSetInstance(v reflect.Value) error {
+	return d.afterSetInstance(v)
+}
+
+func (d *customMethodBeanDefinition) DestroyInstance(v reflect.Value) error {
+	if d.untrack(v) {
+		return d.destroyInstance(v)
+	}
+	return nil
+}
+
+func (d *customMethodBeanDefinition) afterSetInstance(i reflect.Value) error {
+	if !i.IsValid() || i.IsNil() {
+		return nil
+	}
+	var errs errors.Errors
+	name := d.lifeCycleFuncs[PreAfterSet]
+	if name != "" {
+		err := d.callByName(i, name)
+		if err != nil {
+			errs.AddError(err)
+		}
+	}
+	if d.t.Implements(InitializingType) {
+		err := i.Interface().(Initializing).BeanAfterSet()
+		if err != nil {
+			errs.AddError(err)
+		}
+	}
+	name = d.lifeCycleFuncs[PostAfterSet]
+	if name != "" {
+		err := d.callByName(i, name)
+		if err != nil {
+			errs.AddError(err)
+		}
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
+func (d *customMethodBeanDefinition) destroyInstance(i reflect.Value) error {
+	if !i.IsValid() || i.IsNil() {
+		return nil
+	}
+	var errs errors.Errors
+	name := d.lifeCycleFuncs[PreDestroy]
+	if name != "" {
+		err := d.callByName(i, name)
+		if err != nil {
+			errs.AddError(err)
+		}
+	}
+
+	if d.t.Implements(DisposableType) {
+		err := i.Interface().(Disposable).BeanDestroy()
+		if err != nil {
+			errs.AddError(err)
+		}
+	}
+
+	name = d.lifeCycleFuncs[PostDestroy]
+	if name != "" {
+		err := d.callByName(i, name)
+		if err != nil {
+			errs.AddError(err)
+		}
+	}
+	if errs.Empty() {
+		return nil
+	}
+	return errs
+}
+
 type singletonFunction struct {
 	once sync.Once
 	f    interface{}
@@ -317,8 +357,8 @@ func (f *singletonFunction) get() interface{} {
 	if ft.Kind() != reflect.Func {
 		panic("Input Type is not a function. ")
 	}
-	if ft.NumOut() != 1 {
-		panic("Input function must ONLY have 1 return value. ")
+	if !VerifyFactoryReturns(ft) {
+		panic("Input function must return 1 value or (value, error). ")
 	}
 
 	rt := ft.Out(0)
@@ -0,0 +1,29 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "reflect"
+
+// 支持替换实例的对象定义，用于BeanPostProcessor包装或替换单例对象
+type Decoratable interface {
+	// 获得单例对象的实例，非单例或者实例尚未创建时返回无效的reflect.Value
+	Instance() reflect.Value
+
+	// 设置注入及获取时返回的对象（如包装原对象的装饰器），v必须可以赋值给对象定义的类型
+	// 注意：初始化、分类及销毁等生命周期方法仍然作用于原对象
+	Decorate(v reflect.Value)
+}
@@ -21,7 +21,9 @@ import (
 	"github.com/xfali/goutils/container/skiplist"
 	"github.com/xfali/neve-core/reflection"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -44,6 +46,13 @@ func NewContainer(opts ...ContainerOpt) *defaultContainer {
 	return ret
 }
 
+// 配置父容器，当前容器中不存在的对象从父容器中获取，自动注入时同时匹配父容器中的对象
+func OptContainerParent(parent Container) ContainerOpt {
+	return func(container *defaultContainer) {
+		container.parent = parent
+	}
+}
+
 // 配置是否开启key缓存，用于提高scan的性能
 // true为开启，false为关闭
 // 默认开启
@@ -54,12 +63,20 @@ func OptContainerEnableCache(flag bool) ContainerOpt {
 }
 
 type elem struct {
-	def   Definition
-	order int
+	def     Definition
+	order   int
+	aliases []string
+	// 缓存的父容器中的对象定义
+	foreign bool
+	// 是否配置了order
+	orderSet bool
+	// 注册序号，用于类型索引按注册顺序排序
+	seq int
 }
 
-func newElem(opts ...RegisterOpt) *elem {
+func newElem(def Definition, opts ...RegisterOpt) *elem {
 	ret := &elem{
+		def:   def,
 		order: defaultOrder,
 	}
 	for _, opt := range opts {
@@ -73,8 +90,24 @@ func compareElem(a, b interface{}) int {
 }
 
 func (e *elem) Set(key string, value interface{}) {
-	if key == KeySetOrder {
+	switch key {
+	case KeySetOrder:
 		e.order = value.(int)
+		e.orderSet = true
+		return
+	case KeySetAliases:
+		if v, ok := value.([]string); ok {
+			for _, alias := range v {
+				if alias != "" {
+					e.aliases = append(e.aliases, alias)
+				}
+			}
+		}
+		return
+	}
+	// 其他配置由对象定义处理
+	if s, ok := e.def.(Setter); ok {
+		s.Set(key, value)
 	}
 }
 
@@ -86,13 +119,71 @@ type pool struct {
 	cache bool
 	dirty bool
 
+	// 类型索引：对象类型 -> 对象定义（同一个对象定义仅记录一次）
+	types map[reflect.Type][]*elem
+	seq   int
+
+	// 冻结后的不可变快照，读操作无锁；冻结后的修改在加锁修改之后重新生成快照
+	frozen   bool
+	snapshot atomic.Value
+
 	locker sync.Mutex
 }
 
+// 容器冻结后的不可变快照
+type poolSnapshot struct {
+	m     map[string]*elem
+	keys  []string
+	types map[reflect.Type][]*elem
+}
+
+func (p *pool) loadSnapshot() *poolSnapshot {
+	if v := p.snapshot.Load(); v != nil {
+		return v.(*poolSnapshot)
+	}
+	return nil
+}
+
+func (p *pool) freeze() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	p.frozen = true
+	p.publishLocked()
+}
+
+func (p *pool) isFrozen() bool {
+	return p.loadSnapshot() != nil
+}
+
+// 冻结后重新生成快照，未冻结时不做处理
+func (p *pool) publishLocked() {
+	if !p.frozen {
+		return
+	}
+	snap := &poolSnapshot{
+		m:     make(map[string]*elem, len(p.m)),
+		keys:  make([]string, 0, len(p.m)),
+		types: make(map[reflect.Type][]*elem, len(p.types)),
+	}
+	for k, v := range p.m {
+		snap.m[k] = v
+	}
+	for x := p.l.First(); x != nil; x = x.Next() {
+		snap.keys = append(snap.keys, x.Value().([]string)...)
+	}
+	// 索引中的slice在修改时重新分配或仅追加，可以直接引用
+	for k, v := range p.types {
+		snap.types[k] = v
+	}
+	p.snapshot.Store(snap)
+}
+
 func newPool(initSize int, cacheKey bool) *pool {
 	ret := &pool{
-		m: make(map[string]*elem, initSize),
-		l: skiplist.New(skiplist.SetKeyCompareFunc(skiplist.CompareInt)),
+		m:     make(map[string]*elem, initSize),
+		l:     skiplist.New(skiplist.SetKeyCompareFunc(skiplist.CompareInt)),
+		types: map[reflect.Type][]*elem{},
 	}
 	if cacheKey {
 		ret.cache = cacheKey
@@ -103,6 +194,10 @@ func newPool(initSize int, cacheKey bool) *pool {
 }
 
 func (p *pool) keys() []string {
+	if snap := p.loadSnapshot(); snap != nil {
+		return snap.keys
+	}
+
 	p.locker.Lock()
 	defer p.locker.Unlock()
 
@@ -132,22 +227,233 @@ func (p *pool) loadOrStore(name string, elem *elem) (*elem, bool) {
 
 	if v, ok := p.m[name]; ok {
 		return v, true
+	}
+	for _, alias := range elem.aliases {
+		if v, ok := p.m[alias]; ok {
+			return v, true
+		}
+	}
+	keys := p.l.Get(elem.order)
+	if keys == nil {
+		keys = []string{name}
+	} else {
+		keys = append(keys.([]string), name)
+	}
+	p.l.Set(elem.order, keys)
+	p.m[name] = elem
+	// 别名仅用于查找，不参与遍历
+	for _, alias := range elem.aliases {
+		p.m[alias] = elem
+	}
+	p.seq++
+	elem.seq = p.seq
+	p.indexLocked(elem)
+	// mark dirty
+	p.dirty = true
+	p.publishLocked()
+	return elem, false
+}
+
+// 将对象定义加入类型索引，父容器中的对象定义不加入索引
+func (p *pool) indexLocked(e *elem) {
+	if e.foreign {
+		return
+	}
+	t := e.def.Type()
+	for _, v := range p.types[t] {
+		if v.def == e.def {
+			return
+		}
+	}
+	p.types[t] = append(p.types[t], e)
+}
+
+func (p *pool) unindexLocked(def Definition) {
+	t := def.Type()
+	es := p.types[t]
+	for i, v := range es {
+		if v.def == def {
+			// 重新分配，避免修改find返回前引用的数组
+			es = append(es[:i:i], es[i+1:]...)
+			break
+		}
+	}
+	if len(es) == 0 {
+		delete(p.types, t)
+	} else {
+		p.types[t] = es
+	}
+}
+
+// 查找类型满足match的对象定义，按order及注册顺序排列
+func (p *pool) find(match func(t reflect.Type) bool) []Definition {
+	var es []*elem
+	if snap := p.loadSnapshot(); snap != nil {
+		for t, v := range snap.types {
+			if match(t) {
+				es = append(es, v...)
+			}
+		}
+	} else {
+		p.locker.Lock()
+		for t, v := range p.types {
+			if match(t) {
+				es = append(es, v...)
+			}
+		}
+		p.locker.Unlock()
+	}
+
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].order != es[j].order {
+			return es[i].order < es[j].order
+		}
+		return es[i].seq < es[j].seq
+	})
+	ret := make([]Definition, len(es))
+	for i, e := range es {
+		ret[i] = e.def
+	}
+	return ret
+}
+
+func (p *pool) alias(name, alias string) error {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	e, ok := p.m[name]
+	if !ok {
+		return errors.New(name + " bean not found. ")
+	}
+	if _, ok := p.m[alias]; ok {
+		return errors.New(alias + " bean is exists. ")
+	}
+	p.m[alias] = e
+	e.aliases = append(e.aliases, alias)
+	p.publishLocked()
+	return nil
+}
+
+// 删除名称对应的元素以及所有指向同一对象定义的名称（如缓存）
+func (p *pool) delete(name string) (*elem, bool) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	e, ok := p.m[name]
+	if !ok {
+		return nil, false
+	}
+	for k, v := range p.m {
+		if v == e || v.def == e.def {
+			p.deleteLocked(k, v)
+		}
+	}
+	p.unindexLocked(e.def)
+	p.publishLocked()
+	return e, true
+}
+
+func (p *pool) deleteLocked(name string, e *elem) {
+	delete(p.m, name)
+	keys := p.l.Get(e.order)
+	if keys == nil {
+		return
+	}
+	old := keys.([]string)
+	ks := make([]string, 0, len(old))
+	for _, k := range old {
+		if k != name {
+			ks = append(ks, k)
+		}
+	}
+	if len(ks) == 0 {
+		p.l.Delete(e.order)
 	} else {
-		keys := p.l.Get(elem.order)
+		p.l.Set(e.order, ks)
+	}
+	// mark dirty
+	p.dirty = true
+}
+
+// 替换名称对应的元素，所有指向原对象定义的名称（如别名、缓存）都指向新的对象定义
+func (p *pool) replace(name string, e *elem) (*elem, error) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	old, ok := p.m[name]
+	if !ok {
+		return nil, errors.New(name + " bean not found. ")
+	}
+	for _, alias := range e.aliases {
+		if v, ok := p.m[alias]; ok && v != old {
+			return nil, errors.New(alias + " bean is exists. ")
+		}
+	}
+	// 查找注册名称（name可能为别名）
+	key := name
+	if keys := p.l.Get(old.order); keys != nil {
+		for _, k := range keys.([]string) {
+			if p.m[k] == old {
+				key = k
+				break
+			}
+		}
+	}
+	e.foreign = old.foreign
+	e.seq = old.seq
+	// 未配置order时保持原有的顺序
+	if !e.orderSet {
+		e.order = old.order
+	}
+	e.aliases = append(append([]string{}, old.aliases...), e.aliases...)
+	for k, v := range p.m {
+		if v == old {
+			p.m[k] = e
+		} else if v.def == old.def {
+			// 元素可能被快照引用，不直接修改
+			ne := *v
+			ne.def = e.def
+			p.m[k] = &ne
+		}
+	}
+	for _, alias := range e.aliases {
+		p.m[alias] = e
+	}
+	p.unindexLocked(old.def)
+	p.indexLocked(e)
+	if old.order != e.order {
+		p.deleteLocked(key, old)
+		keys := p.l.Get(e.order)
 		if keys == nil {
-			keys = []string{name}
+			keys = []string{key}
 		} else {
-			keys = append(keys.([]string), name)
+			keys = append(keys.([]string), key)
+		}
+		p.l.Set(e.order, keys)
+		p.m[key] = e
+	}
+	p.publishLocked()
+	return old, nil
+}
+
+func (p *pool) contains(def Definition) bool {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+
+	for _, v := range p.m {
+		if v.def == def {
+			return true
 		}
-		p.l.Set(elem.order, keys)
-		p.m[name] = elem
-		// mark dirty
-		p.dirty = true
-		return elem, false
 	}
+	return false
 }
 
 func (p *pool) load(name string) (*elem, bool) {
+	if snap := p.loadSnapshot(); snap != nil {
+		v, ok := snap.m[name]
+		return v, ok
+	}
+
 	p.locker.Lock()
 	defer p.locker.Unlock()
 
@@ -161,6 +467,7 @@ func (p *pool) load(name string) (*elem, bool) {
 type defaultContainer struct {
 	enableCache bool
 	objectPool  *pool
+	parent      Container
 }
 
 func (c *defaultContainer) Register(o interface{}, opts ...RegisterOpt) error {
@@ -168,6 +475,25 @@ func (c *defaultContainer) Register(o interface{}, opts ...RegisterOpt) error {
 }
 
 func (c *defaultContainer) RegisterByName(name string, o interface{}, opts ...RegisterOpt) error {
+	if c.objectPool.isFrozen() {
+		return ErrContainerFrozen
+	}
+	return c.register(name, o, opts...)
+}
+
+func (c *defaultContainer) RegisterDynamic(name string, o interface{}, opts ...RegisterOpt) error {
+	return c.register(name, o, opts...)
+}
+
+func (c *defaultContainer) Freeze() {
+	c.objectPool.freeze()
+}
+
+func (c *defaultContainer) IsFrozen() bool {
+	return c.objectPool.isFrozen()
+}
+
+func (c *defaultContainer) register(name string, o interface{}, opts ...RegisterOpt) error {
 	beanDefinition, err := CreateBeanDefinition(o)
 	if err != nil {
 		return err
@@ -184,21 +510,27 @@ func (c *defaultContainer) RegisterByName(name string, o interface{}, opts ...Re
 		}
 	}
 
-	elem := newElem(opts...)
-	elem.def = beanDefinition
+	elem := newElem(beanDefinition, opts...)
 	_, loaded := c.objectPool.loadOrStore(name, elem)
 	if loaded {
-		return errors.New(name + " bean is exists. ")
+		return errors.New(name + " bean or alias is exists. ")
 	}
 	return nil
 }
 
+func (c *defaultContainer) Alias(name, alias string) error {
+	if alias == "" {
+		return errors.New("Alias is empty. ")
+	}
+	return c.objectPool.alias(name, alias)
+}
+
 func (c *defaultContainer) PutDefinition(name string, definition Definition) error {
 	if definition == nil {
 		return errors.New("Definition is nil. ")
 	}
-	elem := newElem()
-	elem.def = definition
+	elem := newElem(definition)
+	elem.foreign = c.parent != nil && !c.objectPool.contains(definition)
 	_, loaded := c.objectPool.loadOrStore(name, elem)
 	if loaded {
 		return errors.New(name + " bean is exists. ")
@@ -206,11 +538,74 @@ func (c *defaultContainer) PutDefinition(name string, definition Definition) err
 	return nil
 }
 
+func (c *defaultContainer) Replace(name string, o interface{}, opts ...RegisterOpt) (Definition, error) {
+	beanDefinition, err := CreateBeanDefinition(o)
+	if err != nil {
+		return nil, err
+	}
+	if beanDefinition == nil {
+		return nil, errors.New("beanDefinition is nil. ")
+	}
+	old, err := c.objectPool.replace(name, newElem(beanDefinition, opts...))
+	if err != nil {
+		return nil, err
+	}
+	return old.def, nil
+}
+
+func (c *defaultContainer) Remove(name string) (Definition, bool) {
+	e, ok := c.objectPool.delete(name)
+	if ok {
+		return e.def, true
+	}
+	return nil, false
+}
+
+func (c *defaultContainer) FindByType(t reflect.Type) []Definition {
+	ret := c.objectPool.find(func(ot reflect.Type) bool {
+		return ot == t
+	})
+	if c.parent != nil {
+		ret = appendDefinitions(ret, FindByType(c.parent, t))
+	}
+	return ret
+}
+
+func (c *defaultContainer) FindAssignable(t reflect.Type) []Definition {
+	ret := c.objectPool.find(func(ot reflect.Type) bool {
+		return ot.AssignableTo(t)
+	})
+	if c.parent != nil {
+		ret = appendDefinitions(ret, FindAssignable(c.parent, t))
+	}
+	return ret
+}
+
+// 追加父容器中的对象定义，已存在的对象定义不重复添加
+func appendDefinitions(defs []Definition, others []Definition) []Definition {
+	for _, o := range others {
+		found := false
+		for _, d := range defs {
+			if d == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			defs = append(defs, o)
+		}
+	}
+	return defs
+}
+
 func (c *defaultContainer) GetDefinition(name string) (Definition, bool) {
 	o, load := c.objectPool.load(name)
 	if load {
 		return o.def, load
 	}
+	if c.parent != nil {
+		return c.parent.GetDefinition(name)
+	}
 	return nil, false
 }
 
@@ -224,17 +619,55 @@ func (c *defaultContainer) Get(name string) (interface{}, bool) {
 
 func (c *defaultContainer) GetByType(o interface{}) bool {
 	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	v = v.Elem()
 	d, ok := c.GetDefinition(reflection.GetTypeName(v.Type()))
-	if ok {
-		v.Set(d.Value())
+	if !ok || !d.Type().AssignableTo(v.Type()) {
+		return false
 	}
-	return false
+	dv := d.Value()
+	if !dv.IsValid() {
+		return false
+	}
+	v.Set(dv)
+	return true
 }
 
 func (c *defaultContainer) Scan(f func(key string, value Definition) bool) {
+	stopped := false
+	c.scanLocal(true, func(key string, value Definition) bool {
+		stopped = !f(key, value)
+		return !stopped
+	})
+	if stopped || c.parent == nil {
+		return
+	}
+	c.parent.Scan(func(key string, value Definition) bool {
+		// 当前容器中的同名对象覆盖父容器中的对象
+		if _, ok := c.objectPool.load(key); ok {
+			return true
+		}
+		return f(key, value)
+	})
+}
+
+func (c *defaultContainer) Parent() Container {
+	return c.parent
+}
+
+func (c *defaultContainer) ScanLocal(f func(key string, value Definition) bool) {
+	c.scanLocal(false, f)
+}
+
+func (c *defaultContainer) scanLocal(withForeign bool, f func(key string, value Definition) bool) {
 	keys := c.objectPool.keys()
 	for _, k := range keys {
 		if v, ok := c.objectPool.load(k); ok {
+			if v.foreign && !withForeign {
+				continue
+			}
 			if !f(k, v.def) {
 				break
 			}
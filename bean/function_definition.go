@@ -26,35 +26,78 @@ import (
 	"sync/atomic"
 )
 
-const (
-	functionDefinitionNone = iota
-	functionDefinitionInjecting
-)
-
 var (
 	DummyType  = reflect.TypeOf((*struct{})(nil)).Elem()
 	DummyValue = reflect.ValueOf(struct{}{})
 )
 
 type functionExDefinition struct {
-	name   string
-	o      interface{}
-	fn     reflect.Value
-	t      reflect.Type
-	status int32
+	meta
+
+	name string
+	o    interface{}
+	fn   reflect.Value
+	t    reflect.Type
 
 	instances    reflect.Value
+	singleton    reflect.Value
 	instanceLock sync.RWMutex
-	initOnce     int32
-	destroyOnce  int32
+	// 创建单例时使用的锁，仅锁定当前对象定义
+	createLock  sync.Mutex
+	created     int32
+	initOnce    int32
+	destroyOnce int32
+}
+
+// 构造方法返回错误时获取对象定义的值（Value）抛出的panic，包含创建失败的对象定义
+// ApplicationContext在启动时将其转换为启动失败的错误
+type CreateBeanError struct {
+	Definition Definition
+	Err        error
+}
+
+func (e *CreateBeanError) Error() string {
+	return fmt.Sprintf("Create bean [%s] failed: %s ", e.Definition.Name(), e.Err.Error())
+}
+
+func (e *CreateBeanError) Unwrap() error {
+	return e.Err
+}
+
+// 检查构造方法的返回值：T或者(T, error)
+func VerifyFactoryReturns(ft reflect.Type) bool {
+	switch ft.NumOut() {
+	case 1:
+		return true
+	case 2:
+		return ft.Out(1) == ErrorType
+	}
+	return false
+}
+
+// 调用构造方法，如果返回错误则抛出CreateBeanError
+// 注入时包装的构造方法（参数为*Creation）传入当前的创建链
+func callFactory(d Definition, fn reflect.Value, chain *Creation) reflect.Value {
+	var args []reflect.Value
+	if ft := fn.Type(); ft.NumIn() == 1 && ft.In(0) == CreationType {
+		args = []reflect.Value{reflect.ValueOf(chain)}
+	}
+	rets := fn.Call(args)
+	if len(rets) == 2 && !rets[1].IsNil() {
+		panic(&CreateBeanError{
+			Definition: d,
+			Err:        rets[1].Interface().(error),
+		})
+	}
+	return rets[0]
 }
 
 func verifyBeanFunctionEx(ft reflect.Type) error {
 	if ft.Kind() != reflect.Func {
 		return errors.New("Param not function ")
 	}
-	if ft.NumOut() != 1 {
-		return errors.New("Bean function must ONLY have 1 return value ")
+	if !VerifyFactoryReturns(ft) {
+		return errors.New("Bean function must return 1 value or (value, error) ")
 	}
 
 	rt := ft.Out(0)
@@ -74,6 +117,7 @@ func newFunctionExDefinition(o interface{}) (Definition, error) {
 	ot := ft.Out(0)
 	fn := reflect.ValueOf(o)
 	ret := &functionExDefinition{
+		meta:      newMeta(Prototype),
 		o:         o,
 		name:      reflection.GetTypeName(ot),
 		fn:        fn,
@@ -92,18 +136,65 @@ func (d *functionExDefinition) Name() string {
 }
 
 func (d *functionExDefinition) Value() reflect.Value {
-	if atomic.CompareAndSwapInt32(&d.status, functionDefinitionNone, functionDefinitionInjecting) {
-		defer atomic.CompareAndSwapInt32(&d.status, functionDefinitionInjecting, functionDefinitionNone)
-		v := d.fn.Call(nil)[0]
-		if v.IsValid() {
-			d.instanceLock.Lock()
-			defer d.instanceLock.Unlock()
-			d.instances.SetMapIndex(v, DummyValue)
+	return d.ValueIn(nil)
+}
+
+// 单例在第一次获取时创建，其他同时获取的调用方等待创建及延迟初始化完成
+func (d *functionExDefinition) ValueIn(chain *Creation) reflect.Value {
+	if v, ok := chain.lookup(d); ok {
+		// 延迟初始化的过程中再次获取同一个单例，返回正在初始化的对象
+		if d.scope == Singleton && v.IsValid() {
+			return d.decoratedOr(v)
 		}
-		return v
-	} else {
 		panic(fmt.Errorf("BeanDefinition: [Function] inject type [%s] Circular dependency ", d.name))
 	}
+	if d.scope != Singleton {
+		return d.create(chain)
+	}
+	if atomic.LoadInt32(&d.created) == 0 {
+		d.createLock.Lock()
+		defer d.createLock.Unlock()
+		if atomic.LoadInt32(&d.created) == 0 {
+			if !d.create(chain).IsValid() {
+				return reflect.Value{}
+			}
+			atomic.StoreInt32(&d.created, 1)
+		}
+	}
+	return d.decoratedOr(d.Instance())
+}
+
+// 调用构造方法创建实例并进行延迟初始化
+func (d *functionExDefinition) create(chain *Creation) reflect.Value {
+	v := callFactory(d, d.fn, chain.with(d, reflect.Value{}))
+	if v.IsValid() {
+		d.instanceLock.Lock()
+		if d.trackInstance() {
+			d.instances.SetMapIndex(v, DummyValue)
+		}
+		if d.scope == Singleton {
+			d.singleton = v
+		}
+		d.instanceLock.Unlock()
+		d.lazyLoadInstance(d, v, chain.with(d, v))
+	}
+	return d.decoratedOr(v)
+}
+
+// 是否跟踪创建的实例，跟踪的实例由对象定义统一执行初始化、分类及销毁：
+// 单例始终跟踪；其他实例仅在对象定义初始化（AfterSet）之前且未设置延迟初始化时（即启动注入时）跟踪，
+// 之后创建的实例不再跟踪，由调用方负责其生命周期（如销毁），避免实例一直被引用而无法回收
+func (d *functionExDefinition) trackInstance() bool {
+	return d.scope == Singleton || (d.lazyInit == nil && atomic.LoadInt32(&d.initOnce) == 0)
+}
+
+func (d *functionExDefinition) Instance() reflect.Value {
+	if d.scope != Singleton {
+		return reflect.Value{}
+	}
+	d.instanceLock.RLock()
+	defer d.instanceLock.RUnlock()
+	return d.singleton
 }
 
 func (d *functionExDefinition) Interface() interface{} {
@@ -121,13 +212,9 @@ func (d *functionExDefinition) AfterSet() error {
 		var errs errors2.Errors
 
 		for _, i := range d.instances.MapKeys() {
-			if i.IsValid() && !i.IsNil() {
-				if v, ok := i.Interface().(Initializing); ok {
-					err := v.BeanAfterSet()
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
+			err := d.afterSetInstance(i)
+			if err != nil {
+				errs.AddError(err)
 			}
 		}
 		if errs.Empty() {
@@ -140,17 +227,17 @@ func (d *functionExDefinition) AfterSet() error {
 
 func (d *functionExDefinition) Destroy() error {
 	if atomic.CompareAndSwapInt32(&d.destroyOnce, 0, 1) {
-		d.instanceLock.RLock()
-		defer d.instanceLock.RUnlock()
+		d.instanceLock.Lock()
+		instances := d.instances.MapKeys()
+		// 已销毁的实例不再跟踪
+		d.instances = reflect.MakeMap(d.instances.Type())
+		d.instanceLock.Unlock()
+
 		var errs errors2.Errors
-		for _, i := range d.instances.MapKeys() {
-			if i.IsValid() && !i.IsNil() {
-				if v, ok := i.Interface().(Disposable); ok {
-					err := v.BeanDestroy()
-					if err != nil {
-						errs.AddError(err)
-					}
-				}
+		for _, i := range instances {
+			err := d.destroyInstance(i)
+			if err != nil {
+				errs.AddError(err)
 			}
 		}
 		if errs.Empty() {
@@ -161,6 +248,51 @@ func (d *functionExDefinition) Destroy() error {
 	return nil
 }
 
+func (d *functionExDefinition) AfterSetInstance(v reflect.Value) error {
+	return d.afterSetInstance(v)
+}
+
+func (d *functionExDefinition) DestroyInstance(v reflect.Value) error {
+	// 未跟踪的非单例实例由调用方负责，直接销毁
+	if d.untrack(v) || (d.scope != Singleton && v.IsValid()) {
+		return d.destroyInstance(v)
+	}
+	return nil
+}
+
+// 停止跟踪实例，如果实例未被跟踪（未创建或已销毁）则返回false
+func (d *functionExDefinition) untrack(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	d.instanceLock.Lock()
+	defer d.instanceLock.Unlock()
+
+	if !d.instances.MapIndex(v).IsValid() {
+		return false
+	}
+	d.instances.SetMapIndex(v, reflect.Value{})
+	return true
+}
+
+func (d *functionExDefinition) afterSetInstance(i reflect.Value) error {
+	if i.IsValid() && !i.IsNil() {
+		if v, ok := i.Interface().(Initializing); ok {
+			return v.BeanAfterSet()
+		}
+	}
+	return nil
+}
+
+func (d *functionExDefinition) destroyInstance(i reflect.Value) error {
+	if i.IsValid() && !i.IsNil() {
+		if v, ok := i.Interface().(Disposable); ok {
+			return v.BeanDestroy()
+		}
+	}
+	return nil
+}
+
 func (d *functionExDefinition) Classify(classifier Classifier) (bool, error) {
 	d.instanceLock.RLock()
 	defer d.instanceLock.RUnlock()
@@ -184,10 +316,15 @@ func (d *functionExDefinition) Classify(classifier Classifier) (bool, error) {
 }
 
 type functionDefinition struct {
+	meta
+
 	name string
 	o    interface{}
 	fn   reflect.Value
 	t    reflect.Type
+
+	once      sync.Once
+	singleton reflect.Value
 }
 
 func verifyBeanFunction(ft reflect.Type) error {
@@ -205,7 +342,7 @@ func verifyBeanFunction(ft reflect.Type) error {
 	return nil
 }
 
-//Deprecated
+// Deprecated
 func newFunctionDefinition(o interface{}) (Definition, error) {
 	ft := reflect.TypeOf(o)
 	err := verifyBeanFunction(ft)
@@ -215,6 +352,7 @@ func newFunctionDefinition(o interface{}) (Definition, error) {
 	ot := ft.Out(0)
 	fn := reflect.ValueOf(o)
 	return &functionDefinition{
+		meta: newMeta(Prototype),
 		o:    o,
 		name: reflection.GetTypeName(ot),
 		fn:   fn,
@@ -231,6 +369,12 @@ func (d *functionDefinition) Name() string {
 }
 
 func (d *functionDefinition) Value() reflect.Value {
+	if d.scope == Singleton {
+		d.once.Do(func() {
+			d.singleton = d.fn.Call(nil)[0]
+		})
+		return d.singleton
+	}
 	return d.fn.Call(nil)[0]
 }
 
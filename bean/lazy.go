@@ -0,0 +1,31 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "reflect"
+
+// 延迟初始化方法
+// d: 延迟初始化的对象定义，v: 第一次获取的实例（构造方法注册时为每个新创建的实例），
+// chain: 包含d的创建链，初始化时的注入应沿用该创建链，详情查看Creation
+type LazyInitFunc func(d Definition, v reflect.Value, chain *Creation)
+
+// 支持延迟初始化的对象定义
+type LazyInitializer interface {
+	// 设置延迟初始化方法，设置后对象定义的实例在第一次被获取时回调f进行注入及初始化。
+	// 未被获取过的延迟初始化对象不会被销毁
+	SetLazyInit(f LazyInitFunc)
+}
@@ -22,6 +22,8 @@ import (
 )
 
 type mapDefinition struct {
+	meta
+
 	name string
 	o    interface{}
 	t    reflect.Type
@@ -30,6 +32,7 @@ type mapDefinition struct {
 func newMapDefinition(o interface{}) (Definition, error) {
 	t := reflect.TypeOf(o)
 	return &mapDefinition{
+		meta: newMeta(Singleton),
 		name: reflection.GetMapName(t),
 		o:    o,
 		t:    t,
@@ -45,7 +48,16 @@ func (d *mapDefinition) Name() string {
 }
 
 func (d *mapDefinition) Value() reflect.Value {
-	return reflect.ValueOf(d.o)
+	v := reflect.ValueOf(d.o)
+	if (d.scope == Prototype || d.scope == Context) && !v.IsNil() {
+		ret := reflect.MakeMapWithSize(d.t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			ret.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return ret
+	}
+	return v
 }
 
 func (d *mapDefinition) Interface() interface{} {
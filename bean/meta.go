@@ -0,0 +1,175 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// bean注册时配置的元数据，由RegisterOpt设置
+type meta struct {
+	scope      Scope
+	primary    bool
+	qualifiers []string
+	lazy       bool
+	attributes map[string]interface{}
+	labels     map[string]string
+
+	lazyInit   LazyInitFunc
+	lazyLock   sync.Mutex
+	lazyLoaded int32
+
+	// 替换单例对象的值，详情查看Decoratable
+	decorated atomic.Value
+}
+
+func newMeta(scope Scope) meta {
+	return meta{
+		scope: scope,
+	}
+}
+
+func (m *meta) Set(key string, value interface{}) {
+	switch key {
+	case KeySetScope:
+		if v, ok := value.(Scope); ok && v != "" {
+			m.scope = v
+		}
+	case KeySetPrimary:
+		if v, ok := value.(bool); ok {
+			m.primary = v
+		}
+	case KeySetQualifier:
+		if v, ok := value.(string); ok && v != "" {
+			m.qualifiers = append(m.qualifiers, v)
+		}
+	case KeySetLazy:
+		if v, ok := value.(bool); ok {
+			m.lazy = v
+		}
+	case KeySetAttribute:
+		if v, ok := value.(Attribute); ok {
+			if m.attributes == nil {
+				m.attributes = map[string]interface{}{}
+			}
+			m.attributes[v.Key] = v.Value
+		}
+	case KeySetLabels:
+		if v, ok := value.([]string); ok {
+			for _, l := range v {
+				k, lv := parseLabel(l)
+				if k == "" {
+					continue
+				}
+				if m.labels == nil {
+					m.labels = map[string]string{}
+				}
+				m.labels[k] = lv
+			}
+		}
+	}
+}
+
+// 解析"key=value"或"key"形式的标签
+func parseLabel(label string) (string, string) {
+	kv := strings.SplitN(label, "=", 2)
+	if len(kv) == 1 {
+		return strings.TrimSpace(kv[0]), ""
+	}
+	return strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+}
+
+func (m *meta) Scope() Scope {
+	return m.scope
+}
+
+func (m *meta) IsPrimary() bool {
+	return m.primary
+}
+
+func (m *meta) Qualifiers() []string {
+	return m.qualifiers
+}
+
+func (m *meta) IsLazy() bool {
+	return m.lazy
+}
+
+func (m *meta) Attribute(key string) (interface{}, bool) {
+	v, ok := m.attributes[key]
+	return v, ok
+}
+
+func (m *meta) Attributes() map[string]interface{} {
+	return m.attributes
+}
+
+func (m *meta) Labels() map[string]string {
+	return m.labels
+}
+
+func (m *meta) SetLazyInit(f LazyInitFunc) {
+	m.lazyInit = f
+}
+
+// 延迟初始化对象，仅在第一次获取时回调，其他同时获取的调用方等待初始化完成
+// 初始化过程中通过字段的循环依赖再次获取（创建链中已包含d）时直接返回
+func (m *meta) lazyLoadOnce(d Definition, v reflect.Value, chain *Creation) {
+	if m.lazyInit == nil || atomic.LoadInt32(&m.lazyLoaded) == 1 {
+		return
+	}
+	if _, ok := chain.lookup(d); ok {
+		return
+	}
+	m.lazyLock.Lock()
+	defer m.lazyLock.Unlock()
+	if atomic.LoadInt32(&m.lazyLoaded) == 0 {
+		m.lazyInit(d, v, chain.with(d, v))
+		atomic.StoreInt32(&m.lazyLoaded, 1)
+	}
+}
+
+// 延迟初始化新创建的实例
+func (m *meta) lazyLoadInstance(d Definition, v reflect.Value, chain *Creation) {
+	if m.lazyInit != nil {
+		atomic.StoreInt32(&m.lazyLoaded, 1)
+		m.lazyInit(d, v, chain)
+	}
+}
+
+// 是否为尚未初始化的延迟初始化对象
+func (m *meta) lazyUnloaded() bool {
+	return m.lazyInit != nil && atomic.LoadInt32(&m.lazyLoaded) == 0
+}
+
+func (m *meta) Decorate(v reflect.Value) {
+	m.decorated.Store(v)
+}
+
+// 如果设置了替换的值则返回替换的值，否则返回v
+func (m *meta) decoratedOr(v reflect.Value) reflect.Value {
+	if m.scope != Singleton {
+		return v
+	}
+	if dv, ok := m.decorated.Load().(reflect.Value); ok && dv.IsValid() {
+		return dv
+	}
+	return v
+}
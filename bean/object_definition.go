@@ -24,6 +24,8 @@ import (
 )
 
 type objectDefinition struct {
+	meta
+
 	name        string
 	o           interface{}
 	t           reflect.Type
@@ -40,6 +42,7 @@ func newObjectDefinition(o interface{}) (Definition, error) {
 		}
 	}
 	return &objectDefinition{
+		meta:        newMeta(Singleton),
 		name:        reflection.GetTypeName(t),
 		o:           o,
 		t:           t,
@@ -57,6 +60,25 @@ func (d *objectDefinition) Name() string {
 }
 
 func (d *objectDefinition) Value() reflect.Value {
+	return d.ValueIn(nil)
+}
+
+func (d *objectDefinition) ValueIn(chain *Creation) reflect.Value {
+	v := reflect.ValueOf(d.o)
+	d.lazyLoadOnce(d, v, chain)
+	if (d.scope == Prototype || d.scope == Context) && !v.IsNil() {
+		// 原型返回注册对象的浅拷贝
+		ret := reflect.New(d.t.Elem())
+		ret.Elem().Set(v.Elem())
+		return ret
+	}
+	return d.decoratedOr(v)
+}
+
+func (d *objectDefinition) Instance() reflect.Value {
+	if d.scope != Singleton {
+		return reflect.Value{}
+	}
 	return reflect.ValueOf(d.o)
 }
 
@@ -79,6 +101,10 @@ func (d *objectDefinition) AfterSet() error {
 }
 
 func (d *objectDefinition) Destroy() error {
+	// 未使用的延迟初始化对象不需要销毁
+	if d.lazyUnloaded() {
+		return nil
+	}
 	// Just run once
 	if atomic.CompareAndSwapInt32(&d.flagDestroy, 0, 1) {
 		if v, ok := d.o.(Disposable); ok {
@@ -88,6 +114,24 @@ func (d *objectDefinition) Destroy() error {
 	return nil
 }
 
+func (d *objectDefinition) AfterSetInstance(v reflect.Value) error {
+	if v.IsValid() && !v.IsNil() {
+		if o, ok := v.Interface().(Initializing); ok {
+			return o.BeanAfterSet()
+		}
+	}
+	return nil
+}
+
+func (d *objectDefinition) DestroyInstance(v reflect.Value) error {
+	if v.IsValid() && !v.IsNil() {
+		if o, ok := v.Interface().(Disposable); ok {
+			return o.BeanDestroy()
+		}
+	}
+	return nil
+}
+
 func (d *objectDefinition) Classify(classifier Classifier) (bool, error) {
 	return classifier.Classify(d.o)
 }
\ No newline at end of file
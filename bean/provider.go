@@ -0,0 +1,30 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "reflect"
+
+// 对象提供者，注入时不获取对象，每次调用Get时才从容器中获取：
+// 单例每次返回同一个实例，多例（Prototype）的构造方法每次调用都创建新的实例。
+// 注入时必须指定名称或者qualifier，如：Service bean.Provider `inject:"userService"`
+// 需要类型时可以注入func() T或者func() (T, error)类型的字段，规则与注入T时一致
+type Provider interface {
+	// 获取对象，对象不存在或者创建失败时返回错误
+	Get() (interface{}, error)
+}
+
+var ProviderType = reflect.TypeOf((*Provider)(nil)).Elem()
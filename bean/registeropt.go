@@ -17,7 +17,18 @@
 package bean
 
 const (
-	KeySetOrder = "register.bean.order"
+	KeySetOrder       = "register.bean.order"
+	KeySetScope       = "register.bean.scope"
+	KeySetPrimary     = "register.bean.primary"
+	KeySetQualifier   = "register.bean.qualifier"
+	KeySetLazy        = "register.bean.lazy"
+	KeySetCondition   = "register.bean.condition"
+	KeySetProfiles    = "register.bean.profiles"
+	KeySetAliases     = "register.bean.aliases"
+	KeySetAttribute   = "register.bean.attribute"
+	KeySetLabels      = "register.bean.labels"
+	KeySetConfig      = "register.bean.configuration"
+	KeySetInjectNames = "register.bean.injectNames"
 )
 
 type Setter interface {
@@ -26,6 +37,17 @@ type Setter interface {
 
 // Bean注册配置，已支持的配置有：
 // * bean.SetOrder(int) 配置bean注入顺序
+// * bean.SetScope(Scope) 配置bean作用域
+// * bean.SetPrimary() 配置bean在自动注入匹配多个对象时优先选择
+// * bean.SetQualifier(string) 配置bean的限定符
+// * bean.SetLazy() 配置bean延迟初始化
+// * bean.OnProperty(string, string)、bean.OnBean(interface{})、bean.OnMissingBean(interface{})、bean.OnCondition(Condition) 配置bean注册条件
+// * bean.SetProfiles(...string) 配置bean所属的profile
+// * bean.SetAliases(...string) 配置bean的别名
+// * bean.SetAttribute(string, interface{}) 配置bean的属性
+// * bean.SetLabels(...string) 配置bean的标签
+// * bean.SetConfiguration() 配置bean为配置类
+// * bean.SetInjectNames(...string) 配置构造方法参数的注入名称
 type RegisterOpt func(setter Setter)
 
 // 配置bean注入顺序
@@ -34,3 +56,130 @@ func SetOrder(order int) RegisterOpt {
 		setter.Set(KeySetOrder, order)
 	}
 }
+
+// 配置bean作用域，所有类型的对象定义均支持：
+// * bean.Singleton 单例，构造方法注册时在第一次获取时创建实例并缓存
+// * bean.Prototype 原型，每次获取都返回新的实例，指针、slice、map注册时返回其浅拷贝
+// * bean.Context 在同一个作用域内只创建一次实例，作用域结束时销毁，详见appcontext.BeanScope
+// 未配置时指针、slice、map默认为Singleton，构造方法默认为Prototype
+func SetScope(scope Scope) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetScope, scope)
+	}
+}
+
+// 配置bean在自动注入时，如果匹配到多个对象则优先选择该bean
+func SetPrimary() RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetPrimary, true)
+	}
+}
+
+// 配置bean的限定符，可多次配置。
+// 注入时可通过tag选项qualifier选择包含该限定符的对象，如：inject:",qualifier=fast"
+func SetQualifier(qualifier string) RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetQualifier, qualifier)
+	}
+}
+
+// 配置bean延迟初始化：容器启动时不对该bean进行注入、分类及初始化，直到第一次通过GetBean或者注入获取时才执行。
+// 未被使用的延迟初始化bean不会被构造和销毁
+func SetLazy() RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetLazy, true)
+	}
+}
+
+// 配置bean注册条件，可配置多个，所有条件都满足时bean才会被保留。
+// 条件在ApplicationContext启动时（注入之前）按注册顺序判断，不满足条件的bean将从容器中移除
+func OnCondition(condition Condition) RegisterOpt {
+	return func(setter Setter) {
+		if condition != nil {
+			setter.Set(KeySetCondition, condition)
+		}
+	}
+}
+
+// 当配置属性key的值等于value时注册bean
+func OnProperty(key, value string) RegisterOpt {
+	return OnCondition(&propertyCondition{
+		key:   key,
+		value: value,
+	})
+}
+
+// 当容器中存在指定的bean时注册bean
+// o: bean名称（string）、类型（reflect.Type）或者该类型的值，接口类型可以使用接口的空指针，如：(*io.Closer)(nil)
+func OnBean(o interface{}) RegisterOpt {
+	return OnCondition(newBeanCondition(o, false))
+}
+
+// 当容器中不存在指定的bean时注册bean，参数规则同OnBean
+func OnMissingBean(o interface{}) RegisterOpt {
+	return OnCondition(newBeanCondition(o, true))
+}
+
+// 配置bean所属的profile，当任意一个profile被激活（neve.profiles.active）时才注册bean
+// 以"!"开头的profile表示该profile未激活时注册，如："!prod"
+func SetProfiles(profiles ...string) RegisterOpt {
+	return func(setter Setter) {
+		if len(profiles) > 0 {
+			setter.Set(KeySetProfiles, profiles)
+		}
+	}
+}
+
+// 配置bean的别名，注入及获取bean时可以使用别名代替注册名称
+func SetAliases(aliases ...string) RegisterOpt {
+	return func(setter Setter) {
+		if len(aliases) > 0 {
+			setter.Set(KeySetAliases, aliases)
+		}
+	}
+}
+
+// bean属性，由SetAttribute设置
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// 配置bean的属性（任意key/value），可多次配置，相同key的属性后配置的覆盖先配置的
+// 可通过bean.AttributeOf、bean.AttributesOf获得，如：bean.SetAttribute("route", "/users")
+func SetAttribute(key string, value interface{}) RegisterOpt {
+	return func(setter Setter) {
+		if key != "" {
+			setter.Set(KeySetAttribute, Attribute{Key: key, Value: value})
+		}
+	}
+}
+
+// 配置bean的标签，格式为"key=value"或"key"（值为空），如：bean.SetLabels("kind=handler", "tier=web")
+// 可通过bean.LabelsOf获得；注入slice或map时可以通过tag选项labels选择包含该标签的对象，如：inject:",labels=kind=handler"
+func SetLabels(labels ...string) RegisterOpt {
+	return func(setter Setter) {
+		if len(labels) > 0 {
+			setter.Set(KeySetLabels, labels)
+		}
+	}
+}
+
+// 配置bean为配置类：名称以Bean结尾的公开方法（如：func (c *Config) DataSourceBean(conf *DBConfig) *DataSource）返回的对象注册为bean
+// 详情查看BeanProvider
+func SetConfiguration() RegisterOpt {
+	return func(setter Setter) {
+		setter.Set(KeySetConfig, true)
+	}
+}
+
+// 配置构造方法参数的注入名称，规则与CustomBeanFactory的InjectNames一致（数量必须与参数个数一致，自动匹配的参数填入""）
+// 名称可以使用配置属性占位符，参数从配置属性中获取并转换为参数类型，如：
+// app.RegisterBean(NewServer, bean.SetInjectNames("${server.host}", "${server.port:8080}", ""))
+func SetInjectNames(names ...string) RegisterOpt {
+	return func(setter Setter) {
+		if len(names) > 0 {
+			setter.Set(KeySetInjectNames, names)
+		}
+	}
+}
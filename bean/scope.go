@@ -0,0 +1,41 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "reflect"
+
+type Scope string
+
+const (
+	// 单例：容器中仅存在一个实例，构造方法在第一次获取时调用
+	Singleton Scope = "singleton"
+	// 原型：每次获取时都返回新的实例
+	// 注意：启动之后由构造方法创建的原型实例不被容器跟踪，调用方负责其销毁
+	Prototype Scope = "prototype"
+	// Context作用域：实例在同一个作用域（绑定context.Context的生命周期）内只创建一次，作用域结束时销毁。
+	// 在作用域之外获取时行为与Prototype一致
+	Context Scope = "context"
+)
+
+// 多实例的对象定义，支持对单个实例执行生命周期方法，用于Prototype及Context作用域的实例管理
+type InstanceLifecycle interface {
+	// 对实例执行初始化回调
+	AfterSetInstance(v reflect.Value) error
+
+	// 销毁实例，销毁后对象定义不再跟踪该实例
+	DestroyInstance(v reflect.Value) error
+}
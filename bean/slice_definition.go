@@ -22,6 +22,8 @@ import (
 )
 
 type sliceDefinition struct {
+	meta
+
 	name string
 	o    interface{}
 	t    reflect.Type
@@ -30,6 +32,7 @@ type sliceDefinition struct {
 func newSliceDefinition(o interface{}) (Definition, error) {
 	t := reflect.TypeOf(o)
 	return &sliceDefinition{
+		meta: newMeta(Singleton),
 		name: reflection.GetSliceName(t),
 		o:    o,
 		t:    t,
@@ -45,7 +48,13 @@ func (d *sliceDefinition) Name() string {
 }
 
 func (d *sliceDefinition) Value() reflect.Value {
-	return reflect.ValueOf(d.o)
+	v := reflect.ValueOf(d.o)
+	if (d.scope == Prototype || d.scope == Context) && !v.IsNil() {
+		ret := reflect.MakeSlice(d.t, v.Len(), v.Len())
+		reflect.Copy(ret, v)
+		return ret
+	}
+	return v
 }
 
 func (d *sliceDefinition) Interface() interface{} {
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"errors"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+// 值类型的对象定义：基础类型（如string、int、time.Duration）及结构体的值
+// 获取及注入时得到的都是注册值的拷贝（结构体为浅拷贝），依赖方修改不会影响容器中的值
+// 值类型的对象不会被注入，也没有初始化及销毁等生命周期
+type valueDefinition struct {
+	meta
+
+	name string
+	o    interface{}
+	t    reflect.Type
+}
+
+// 是否为值类型的对象定义支持的类型
+func IsValueKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Struct,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+func newValueDefinition(o interface{}) (Definition, error) {
+	t := reflect.TypeOf(o)
+	if !IsValueKind(t.Kind()) {
+		return nil, errors.New("Value bean must be basic type or struct. ")
+	}
+	return &valueDefinition{
+		meta: newMeta(Singleton),
+		name: reflection.GetTypeName(t),
+		o:    o,
+		t:    t,
+	}, nil
+}
+
+func (d *valueDefinition) Type() reflect.Type {
+	return d.t
+}
+
+func (d *valueDefinition) Name() string {
+	return d.name
+}
+
+func (d *valueDefinition) Value() reflect.Value {
+	// 返回不可寻址的拷贝，赋值时再次复制
+	return reflect.ValueOf(d.o)
+}
+
+func (d *valueDefinition) Interface() interface{} {
+	return d.o
+}
+
+func (d *valueDefinition) IsObject() bool {
+	return false
+}
+
+func (d *valueDefinition) AfterSet() error {
+	return nil
+}
+
+func (d *valueDefinition) Destroy() error {
+	return nil
+}
+
+func (d *valueDefinition) Classify(classifier Classifier) (bool, error) {
+	return false, nil
+}
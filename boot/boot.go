@@ -18,9 +18,11 @@ package boot
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"github.com/xfali/neve-core"
 	"github.com/xfali/neve-core/bean"
+	"io"
 	"os"
 	"sync"
 )
@@ -30,6 +32,8 @@ const EnvNameConfigFile = "NEVE_CONFIG_FILE"
 var (
 	// 默认的配置路径
 	ConfigPath = "application.yaml"
+	// 为true时Run仅检查依赖并输出检查报告，不启动应用，检查失败时以非0状态码退出
+	CheckOnly = false
 
 	creator func() neve.Application = defaultCreator
 	gApp    neve.Application
@@ -72,6 +76,7 @@ func defaultCreator() neve.Application {
 		ConfigPath = conf
 	}
 	flag.StringVar(&ConfigPath, "f", ConfigPath, "Application configuration file path.")
+	flag.BoolVar(&CheckOnly, "check", CheckOnly, "Verify bean wiring without starting the application, exit non-zero on failure.")
 	flag.Parse()
 	return neve.NewFileConfigApplication(ConfigPath)
 }
@@ -84,13 +89,50 @@ func instance() neve.Application {
 }
 
 // Run 启动全局Application
+// 配置CheckOnly（或者命令行参数-check）时仅检查依赖，检查失败时以状态码1退出
 func Run() error {
-	return instance().Run()
+	// 命令行参数在创建全局Application时解析
+	app := instance()
+	if CheckOnly {
+		check()
+		return nil
+	}
+	return app.Run()
+}
+
+// Verify 检查全局Application的依赖并将检查报告输出到w，不会启动应用
+// 存在无法注入、无法确定的依赖或者循环依赖时返回错误
+func Verify(w io.Writer) error {
+	app, ok := instance().(neve.VerifiableApplication)
+	if !ok {
+		return errors.New("Application does not support verify. ")
+	}
+	report, err := app.Verify()
+	if report != nil {
+		if werr := report.Write(w); werr != nil && err == nil {
+			err = werr
+		}
+	} else if err != nil {
+		_, _ = io.WriteString(w, err.Error()+"\n")
+	}
+	return err
 }
 
 // RunWithContext 带context的启动全局Application
+// 配置CheckOnly时行为同Run
 func RunWithContext(ctx context.Context) error {
-	return instance().RunWithContext(ctx)
+	app := instance()
+	if CheckOnly {
+		check()
+		return nil
+	}
+	return app.RunWithContext(ctx)
+}
+
+func check() {
+	if err := Verify(os.Stdout); err != nil {
+		os.Exit(1)
+	}
 }
 
 // Stop 强制停止全局Application
@@ -1,6 +1,6 @@
 module github.com/xfali/neve-core
 
-go 1.14
+go 1.18
 
 require (
 	github.com/xfali/fig v0.1.3
@@ -8,5 +8,9 @@ require (
 	github.com/xfali/neve-utils v0.0.1
 	github.com/xfali/reflection v0.0.0-20220705135531-464ba3201671
 	github.com/xfali/xlog v0.1.5
+)
+
+require (
+	github.com/ghodss/yaml v1.0.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+// 静态分析得到的依赖：在注入点Point依赖对象定义Definition
+type Dependency struct {
+	Point      InjectPoint
+	Definition bean.Definition
+}
+
+// 静态分析构造方法（或者CustomBeanFactory）参数的依赖，不会调用构造方法
+// 参数的匹配规则与注入时一致，CustomBeanFactory使用InjectNames返回的名称匹配，无法匹配的参数被忽略
+func FactoryDependencies(c bean.Container, o interface{}, manager ListenerManager) []Dependency {
+	var names []string
+	if b, ok := o.(bean.CustomBeanFactory); ok {
+		o = b.BeanFactory()
+		names = b.InjectNames()
+	}
+	ft := reflect.TypeOf(o)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil
+	}
+	var ret []Dependency
+	for i := 0; i < ft.NumIn(); i++ {
+		// provider在调用时才获取对象，不构成创建时的依赖
+		if _, ok := providerTarget(ft.In(i)); ok {
+			continue
+		}
+		name := ""
+		if i < len(names) {
+			name, _ = manager.ParseListener(names[i])
+		}
+		ds, _ := resolveStatic(c, name, ft.In(i))
+		for _, d := range ds {
+			ret = append(ret, Dependency{Point: ParamPoint("", i), Definition: d})
+		}
+	}
+	return ret
+}
+
+// 静态分析对象中使用InjectTagName标记的字段的依赖，不会进行注入
+// 字段的匹配规则与注入时一致（包括匿名嵌入结构体及嵌套结构体中的字段），无法匹配的字段被忽略
+func FieldDependencies(c bean.Container, o interface{}, manager ListenerManager) []Dependency {
+	t := reflect.TypeOf(o)
+	if t == nil {
+		return nil
+	}
+	var ret []Dependency
+	visitInjectFields(c, t, InjectTagName, manager, false, func(path, tagAll string, ft reflect.Type) {
+		if _, ok := providerTarget(ft); ok {
+			return
+		}
+		tag, _ := manager.ParseListener(tagAll)
+		ds, _ := resolveStatic(c, tag, ft)
+		for _, d := range ds {
+			ret = append(ret, Dependency{Point: FieldPoint(path), Definition: d})
+		}
+	})
+	return ret
+}
+
+// 静态遍历类型t中使用tagName标记的字段，规则与注入时一致：
+// 匿名嵌入结构体以及作为嵌套结构体注入的字段不会回调，而是继续遍历其中的字段
+// fn的参数为字段路径（如：Deps.Repo）、tag以及字段类型
+func visitInjectFields(c bean.Container, t reflect.Type, tagName string, manager ListenerManager, recursive bool,
+	fn func(path, tagAll string, ft reflect.Type)) {
+	visitFields(c, t, tagName, manager, recursive, "", map[reflect.Type]bool{}, fn)
+}
+
+func visitFields(c bean.Container, t reflect.Type, tagName string, manager ListenerManager, recursive bool,
+	prefix string, visiting map[reflect.Type]bool, fn func(path, tagAll string, ft reflect.Type)) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		path := prefix + field.Name
+		tagAll, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			if field.Anonymous && hasInjectFields(field.Type, tagName) {
+				visitFields(c, field.Type, tagName, manager, recursive, path+".", visiting, fn)
+			}
+			continue
+		}
+		tag, _ := manager.ParseListener(tagAll)
+		if isNestedField(c, tag, field.Type, tagName, recursive) {
+			visitFields(c, field.Type, tagName, manager, recursive, path+".", visiting, fn)
+			continue
+		}
+		fn(path, tagAll, field.Type)
+	}
+}
+
+// 按照注入时的规则查找类型为t的值依赖的对象定义，slice及map返回所有组成元素的对象定义
+// 无法注入时返回与注入时一致的错误，匹配到多个无法确定的候选对象时返回*ambiguousError
+func resolveStatic(c bean.Container, name string, t reflect.Type) ([]bean.Definition, error) {
+	// 配置属性占位符不依赖对象
+	if _, ok := parsePlaceholder(name); ok {
+		return nil, nil
+	}
+	name, opt := parseInjectName(name)
+	if pt, ok := providerTarget(t); ok {
+		d, err := resolveProvider(c, name, opt, pt)
+		if err != nil {
+			return nil, err
+		}
+		return []bean.Definition{d}, nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map:
+		var typeName string
+		if t.Kind() == reflect.Slice {
+			typeName = reflection.GetSliceName(t)
+		} else {
+			if t.Key().Kind() != reflect.String {
+				return nil, errors.New("Key type must be string. ")
+			}
+			typeName = reflection.GetMapName(t)
+		}
+		if name == "" && !opt.filtered() {
+			name = typeName
+		}
+		if name != "" {
+			if d, ok := c.GetDefinition(name); ok {
+				if v, ok := d.(*cachedDefinition); ok {
+					return v.sources, nil
+				}
+				return []bean.Definition{d}, nil
+			}
+		}
+		var ret []bean.Definition
+		for _, d := range bean.FindAssignable(c, t.Elem()) {
+			if opt.match(d) {
+				ret = append(ret, d)
+			}
+		}
+		if len(ret) == 0 {
+			if t.Kind() == reflect.Slice {
+				return nil, errors.New("Slice Inject nothing, cannot find any Implementation: " + typeName)
+			}
+			return nil, errors.New("Map Inject nothing, cannot find any Implementation: " + typeName)
+		}
+		return ret, nil
+	case reflect.Interface, reflect.Ptr:
+		if t.Kind() == reflect.Ptr && t.Elem().Kind() != reflect.Struct {
+			return nil, errors.New("Cannot inject this kind: " + t.Elem().Name())
+		}
+		if name == "" && opt.qualifier == "" {
+			if t.Kind() == reflect.Interface {
+				d, err := selectPrimary(c, t)
+				if err != nil {
+					return nil, err
+				}
+				if d != nil {
+					return []bean.Definition{d}, nil
+				}
+			}
+			name = reflection.GetTypeName(t)
+		}
+		if name != "" {
+			if d, ok := c.GetDefinition(name); ok {
+				return []bean.Definition{d}, nil
+			}
+		}
+		// 指针类型仅在指定qualifier时自动匹配
+		if t.Kind() == reflect.Ptr && opt.qualifier == "" {
+			return nil, errors.New("Inject nothing, cannot find any instance of  " + reflection.GetTypeName(t))
+		}
+		d, err := selectCandidate(c, t, opt.qualifier)
+		if err != nil {
+			return nil, err
+		}
+		return []bean.Definition{d}, nil
+	}
+	if !bean.IsValueKind(t.Kind()) {
+		return nil, errors.New("Cannot inject this kind: " + t.Name())
+	}
+	// 基础类型及结构体的值仅按名称或者qualifier匹配
+	if name == "" && opt.qualifier == "" {
+		name = reflection.GetTypeName(t)
+	}
+	d, ok := c.GetDefinition(name)
+	if !ok {
+		if opt.qualifier == "" {
+			return nil, errors.New("Inject nothing, cannot find any instance of  " + reflection.GetTypeName(t))
+		}
+		var err error
+		d, err = selectCandidate(c, t, opt.qualifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dt := d.Type()
+	if !dt.AssignableTo(t) && (dt.Kind() != t.Kind() || !dt.ConvertibleTo(t)) {
+		return nil, fmt.Errorf("Inject value: bean %s type %s cannot assign to %s. ", name,
+			reflection.GetTypeName(dt), reflection.GetTypeName(t))
+	}
+	return []bean.Definition{d}, nil
+}
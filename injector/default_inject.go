@@ -19,6 +19,7 @@ package injector
 import (
 	"errors"
 	"fmt"
+	"github.com/xfali/fig"
 	"github.com/xfali/neve-core/bean"
 	"github.com/xfali/neve-core/reflection"
 	reflectx "github.com/xfali/reflection"
@@ -29,23 +30,76 @@ import (
 )
 
 const (
-	defaultInjectTagName    = "inject"
-	defaultRequiredTagField = "required"
-	defaultOmitTagField     = "omiterror"
+	defaultInjectTagName     = "inject"
+	defaultRequiredTagField  = "required"
+	defaultOmitTagField      = "omiterror"
+	defaultQualifierTagField = "qualifier"
+	defaultLabelsTagField    = "labels"
 )
 
 var (
-	InjectTagName    = defaultInjectTagName
-	RequiredTagField = defaultRequiredTagField
-	OmitTagField     = defaultOmitTagField
+	InjectTagName     = defaultInjectTagName
+	RequiredTagField  = defaultRequiredTagField
+	OmitTagField      = defaultOmitTagField
+	QualifierTagField = defaultQualifierTagField
+	LabelsTagField    = defaultLabelsTagField
 )
 
+// 注入选项，在tag中以key=value的形式配置，如：inject:"name,qualifier=fast"
+type injectOption struct {
+	qualifier string
+	// slice及map注入时选择包含所有标签的对象，可配置多个，如：inject:",labels=kind=handler,labels=tier=web"
+	labels []string
+}
+
+// 是否配置了用于筛选对象的选项，配置时不使用默认名称查找且不缓存注入结果
+func (o injectOption) filtered() bool {
+	return o.qualifier != "" || len(o.labels) > 0
+}
+
+// 判断对象定义是否满足注入选项
+func (o injectOption) match(d bean.Definition) bool {
+	if o.qualifier != "" && !bean.HasQualifier(d, o.qualifier) {
+		return false
+	}
+	return len(o.labels) == 0 || bean.MatchLabels(d, o.labels...)
+}
+
+// 从注入名称中解析名称及注入选项
+func parseInjectName(name string) (string, injectOption) {
+	opt := injectOption{}
+	strs := strings.Split(name, ",")
+	for _, v := range strs[1:] {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(kv[0]) {
+		case QualifierTagField:
+			opt.qualifier = strings.TrimSpace(kv[1])
+		case LabelsTagField:
+			opt.labels = append(opt.labels, strings.TrimSpace(kv[1]))
+		}
+	}
+	return strs[0], opt
+}
+
+// 使用injectBasic注入的基础类型
+var basicKinds = []reflect.Kind{
+	reflect.Bool, reflect.String,
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+	reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+}
+
 type defaultInjector struct {
 	logger    xlog.Logger
 	actuators map[reflect.Kind]Actuator
 	lm        ListenerManager
 	tagName   string
 	recursive bool
+	// 用于解析注入名称中的配置属性占位符
+	props fig.Properties
 }
 
 type Opt func(*defaultInjector)
@@ -60,6 +114,10 @@ func New(opts ...Opt) *defaultInjector {
 		reflect.Struct:    ret.injectStruct,
 		reflect.Slice:     ret.injectSlice,
 		reflect.Map:       ret.injectMap,
+		reflect.Func:      ret.injectProvider,
+	}
+	for _, k := range basicKinds {
+		ret.actuators[k] = ret.injectBasic
 	}
 	ret.lm = NewListenerManager(ret.logger)
 	for _, opt := range opts {
@@ -72,6 +130,10 @@ func (injector *defaultInjector) SetListenerManager(manager ListenerManager) {
 	injector.lm = manager
 }
 
+func (injector *defaultInjector) SetProperties(props fig.Properties) {
+	injector.props = props
+}
+
 func (injector *defaultInjector) CanInject(o interface{}) bool {
 	v := reflect.ValueOf(o)
 	if v.Kind() == reflect.Interface {
@@ -104,30 +166,53 @@ func (injector *defaultInjector) Inject(c bean.Container, o interface{}) error {
 	return errors.New("Type Not support. ")
 }
 
+// 注入结构体中使用tag标记的字段，包括匿名嵌入结构体以及未注册为对象的嵌套结构体中的字段
 func (injector *defaultInjector) injectStructFields(c bean.Container, v reflect.Value) error {
+	return injector.injectFields(c, v.Type(), v, "", map[reflect.Type]bool{})
+}
+
+// root为最外层的结构体类型，prefix为当前结构体的字段路径前缀（如："Deps."），用于生成注入点及错误信息
+func (injector *defaultInjector) injectFields(c bean.Container, root reflect.Type, v reflect.Value, prefix string, visiting map[reflect.Type]bool) error {
 	t := v.Type()
 	if t.Kind() != reflect.Struct {
 		return errors.New("result must be struct ptr")
 	}
+	if visiting[t] {
+		return fmt.Errorf("Inject failed: Field [%s: %s] error: recursive struct %s ",
+			reflection.GetTypeName(root), strings.TrimSuffix(prefix, "."), reflection.GetTypeName(t))
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
 
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
+		fieldValue := v.Field(i)
+		path := prefix + field.Name
 		tagAll, ok := field.Tag.Lookup(injector.tagName)
-		if ok {
-			tag, listeners := injector.lm.ParseListener(tagAll)
-			fieldValue := v.Field(i)
-			fieldType := fieldValue.Type()
-			if fieldType.Kind() == reflect.Ptr {
-				fieldType = fieldType.Elem()
+		if !ok {
+			// 匿名嵌入的结构体
+			if isEmbeddedInjectable(field, fieldValue, injector.tagName) {
+				if err := injector.injectNested(c, root, fieldValue, path, visiting); err != nil {
+					return err
+				}
 			}
-			err := injector.InjectValue(c, tag, fieldValue)
+			continue
+		}
+		tag, listeners := injector.lm.ParseListener(tagAll)
+		var err error
+		if isNestedField(c, tag, field.Type, injector.tagName, injector.recursive) {
+			err = injector.injectNested(c, root, fieldValue, path, visiting)
+		} else {
+			err = injector.InjectValue(withInjectPoint(c, FieldPoint(path)), tag, fieldValue)
 			if err != nil {
 				err = fmt.Errorf("Inject failed: Field [%s: %s] error: %s\n ",
-					reflection.GetTypeName(t), field.Name, err.Error())
-				//injector.logger.Errorln(errStr)
-				for _, l := range listeners {
-					l.OnInjectFailed(err)
-				}
+					reflection.GetTypeName(root), path, err.Error())
+			}
+		}
+		if err != nil {
+			//injector.logger.Errorln(errStr)
+			for _, l := range listeners {
+				l.OnInjectFailed(err)
 			}
 		}
 	}
@@ -149,6 +234,9 @@ func (injector *defaultInjector) InjectValue(c bean.Container, name string, v re
 		t = t.Elem()
 	}
 	if v.CanSet() {
+		if p, ok := parsePlaceholder(name); ok {
+			return injector.injectProperty(p, v)
+		}
 		actuate := injector.actuators[t.Kind()]
 		if actuate == nil {
 			return errors.New("Cannot inject this kind: " + t.Name())
@@ -161,53 +249,144 @@ func (injector *defaultInjector) InjectValue(c bean.Container, name string, v re
 
 func (injector *defaultInjector) injectInterface(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
-	if name == "" {
+	if vt == bean.ProviderType {
+		return injector.injectProvider(c, name, v)
+	}
+	name, opt := parseInjectName(name)
+	if name == "" && opt.qualifier == "" {
+		// 未指定名称时primary对象优先于以默认名称注册的对象
+		d, err := selectPrimary(c, vt)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			v.Set(valueOf(c, d))
+			recordDependency(c, d)
+			return nil
+		}
 		name = reflection.GetTypeName(vt)
 	}
-	o, ok := c.GetDefinition(name)
-	if ok {
-		v.Set(o.Value())
-		return nil
-	} else {
-		// 自动注入
-		var matchValues []bean.Definition
-		c.Scan(func(key string, value bean.Definition) bool {
-			// 指定名称注册的对象直接跳过，因为在container.Get未满足，所以认定不是用户想要注入的对象
-			if key != value.Name() {
+	if name != "" {
+		o, ok := c.GetDefinition(name)
+		if ok {
+			v.Set(valueOf(c, o))
+			recordDependency(c, o)
+			return nil
+		}
+	}
+	// 自动注入
+	d, err := selectCandidate(c, vt, opt.qualifier)
+	if err != nil {
+		return err
+	}
+	v.Set(valueOf(c, d))
+	recordDependency(c, d)
+	if opt.qualifier == "" {
+		// cache to container
+		err = c.PutDefinition(reflection.GetTypeName(vt), d)
+		if err != nil {
+			injector.logger.Warnln(err)
+		}
+	}
+	return nil
+}
+
+// 选择类型可以赋值给vt的primary对象（不限注册名称），不存在时返回nil，存在多个时返回*ambiguousError
+func selectPrimary(c bean.Container, vt reflect.Type) (bean.Definition, error) {
+	var names []string
+	var primaries []bean.Definition
+	c.Scan(func(key string, value bean.Definition) bool {
+		if !bean.IsPrimary(value) || !value.Type().AssignableTo(vt) {
+			return true
+		}
+		for _, d := range primaries {
+			if d == value {
 				return true
 			}
-			ot := value.Type()
-			if ot.AssignableTo(vt) {
-				matchValues = append(matchValues, value)
-				if len(matchValues) > 1 {
-					panic("Auto Inject bean found more than 1")
-				}
+		}
+		names = append(names, key)
+		primaries = append(primaries, value)
+		return true
+	})
+	switch len(primaries) {
+	case 0:
+		return nil, nil
+	case 1:
+		return primaries[0], nil
+	}
+	return nil, &ambiguousError{fmt.Errorf("Auto Inject bean %s found more than 1 primary candidates: [%s] ", reflection.GetTypeName(vt), strings.Join(names, ", "))}
+}
+
+// 自动注入匹配到多个候选对象且无法确定时的错误
+type ambiguousError struct {
+	error
+}
+
+// 自动注入时选择可注入的对象定义：
+// 1、未指定qualifier时匹配以默认名称注册的对象以及配置为primary的对象（不限注册名称）；指定qualifier时匹配所有包含该qualifier的对象
+// 2、如果匹配到多个对象，则选择配置为primary的对象
+// 3、仍然无法确定时返回包含所有候选对象名称的错误
+func selectCandidate(c bean.Container, vt reflect.Type, qualifier string) (bean.Definition, error) {
+	var names []string
+	var candidates []bean.Definition
+	c.Scan(func(key string, value bean.Definition) bool {
+		if qualifier == "" {
+			// 指定名称注册的非primary对象直接跳过，因为在container.Get未满足，所以认定不是用户想要注入的对象
+			if key != value.Name() && !bean.IsPrimary(value) {
 				return true
 			}
+		} else if !bean.HasQualifier(value, qualifier) {
 			return true
-		})
-		if len(matchValues) == 1 {
-			v.Set(matchValues[0].Value())
-			// cache to container
-			err := c.PutDefinition(reflection.GetTypeName(vt), matchValues[0])
-			if err != nil {
-				injector.logger.Warnln(err)
+		}
+		if !value.Type().AssignableTo(vt) {
+			return true
+		}
+		for _, d := range candidates {
+			if d == value {
+				return true
 			}
-			return nil
 		}
+		names = append(names, key)
+		candidates = append(candidates, value)
+		return true
+	})
+
+	switch len(candidates) {
+	case 0:
+		if qualifier != "" {
+			return nil, fmt.Errorf("Inject nothing, cannot find any Implementation: %s with qualifier: %s ", reflection.GetTypeName(vt), qualifier)
+		}
+		return nil, errors.New("Inject nothing, cannot find any Implementation: " + reflection.GetTypeName(vt))
+	case 1:
+		return candidates[0], nil
 	}
-	return errors.New("Inject nothing, cannot find any Implementation: " + reflection.GetTypeName(vt))
+
+	var primary bean.Definition
+	for _, d := range candidates {
+		if bean.IsPrimary(d) {
+			if primary != nil {
+				return nil, &ambiguousError{fmt.Errorf("Auto Inject bean %s found more than 1 primary candidates: [%s] ", reflection.GetTypeName(vt), strings.Join(names, ", "))}
+			}
+			primary = d
+		}
+	}
+	if primary == nil {
+		return nil, &ambiguousError{fmt.Errorf("Auto Inject bean %s found more than 1 candidates: [%s] ", reflection.GetTypeName(vt), strings.Join(names, ", "))}
+	}
+	return primary, nil
 }
 
 func (injector *defaultInjector) injectSlice(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
-	if name == "" {
+	name, opt := parseInjectName(name)
+	if name == "" && !opt.filtered() {
 		name = reflection.GetSliceName(vt)
 	}
 	elemType := vt.Elem()
 	o, ok := c.GetDefinition(name)
-	if ok {
-		dv := o.Value()
+	if ok && name != "" {
+		dv := valueOf(c, o)
+		recordDependencies(c, o)
 		n, err := reflectx.SetOrCopySlice(v, dv, true)
 		if n != dv.Len() {
 			injector.logger.Infof("Set slice source have %d elements set %d elements", dv.Len(), n)
@@ -215,19 +394,28 @@ func (injector *defaultInjector) injectSlice(c bean.Container, name string, v re
 		return err
 	} else {
 		//自动注入
+		// 与使用缓存注入时一致，覆盖字段原有的值（重新注入时不会重复追加）
 		destTmp := sliceAppender{
-			v:        v,
+			c:        c,
+			v:        reflect.Zero(vt),
 			elemType: elemType,
+			opt:      opt,
 		}
 		c.Scan(destTmp.Scan)
 		destTmp.Set(v)
 		if v.Len() > 0 {
+			for _, d := range destTmp.defs {
+				recordDependency(c, d)
+			}
+			if opt.filtered() {
+				return nil
+			}
 			// cache to container
-			bean, err := bean.CreateBeanDefinition(v.Interface())
+			d, err := newCachedDefinition(v.Interface(), destTmp.defs)
 			if err != nil {
 				injector.logger.Warnln(err)
 			}
-			err = c.PutDefinition(reflection.GetSliceName(vt), bean)
+			err = c.PutDefinition(reflection.GetSliceName(vt), d)
 			if err != nil {
 				injector.logger.Warnln(err)
 			}
@@ -239,14 +427,16 @@ func (injector *defaultInjector) injectSlice(c bean.Container, name string, v re
 
 func (injector *defaultInjector) injectMap(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
-	if name == "" {
+	name, opt := parseInjectName(name)
+	if name == "" && !opt.filtered() {
 		name = reflection.GetMapName(vt)
 	}
 	keyType := vt.Key()
 	elemType := vt.Elem()
 	o, ok := c.GetDefinition(name)
-	if ok {
-		dv := o.Value()
+	if ok && name != "" {
+		dv := valueOf(c, o)
+		recordDependencies(c, o)
 		n, err := reflectx.SetOrCopyMap(v, dv, true)
 		if n != dv.Len() {
 			injector.logger.Infof("Set map source have %d elements set %d elements", dv.Len(), n)
@@ -258,17 +448,25 @@ func (injector *defaultInjector) injectMap(c bean.Container, name string, v refl
 		}
 		//自动注入
 		destTmp := mapPutter{
+			c:        c,
 			v:        v,
 			elemType: elemType,
+			opt:      opt,
 		}
 		c.Scan(destTmp.Scan)
 		if v.Len() > 0 {
+			for _, d := range destTmp.defs {
+				recordDependency(c, d)
+			}
+			if opt.filtered() {
+				return nil
+			}
 			// cache to container
-			bean, err := bean.CreateBeanDefinition(v.Interface())
+			d, err := newCachedDefinition(v.Interface(), destTmp.defs)
 			if err != nil {
 				injector.logger.Warnln(err)
 			}
-			err = c.PutDefinition(reflection.GetMapName(vt), bean)
+			err = c.PutDefinition(reflection.GetMapName(vt), d)
 			if err != nil {
 				injector.logger.Warnln(err)
 			}
@@ -278,16 +476,67 @@ func (injector *defaultInjector) injectMap(c bean.Container, name string, v refl
 	return errors.New("Map Inject nothing, cannot find any Implementation: " + reflection.GetMapName(vt))
 }
 
+// 注入基础类型的值（如string、int、time.Duration），值被复制到字段中
+// 仅按名称（未指定时为类型名称）或者qualifier匹配，不会自动匹配其他同类型的对象
+func (injector *defaultInjector) injectBasic(c bean.Container, name string, v reflect.Value) error {
+	vt := v.Type()
+	if vt.Kind() == reflect.Ptr {
+		return errors.New("Cannot inject this kind: " + reflection.GetTypeName(vt))
+	}
+	name, opt := parseInjectName(name)
+	if name == "" && opt.qualifier == "" {
+		name = reflection.GetTypeName(vt)
+	}
+	o, ok := c.GetDefinition(name)
+	if !ok {
+		if opt.qualifier == "" {
+			return errors.New("Inject nothing, cannot find any instance of  " + reflection.GetTypeName(vt))
+		}
+		var err error
+		o, err = selectCandidate(c, vt, opt.qualifier)
+		if err != nil {
+			return err
+		}
+	}
+	ot := o.Type()
+	ov := valueOf(c, o)
+	if ot.AssignableTo(vt) {
+		v.Set(ov)
+	} else if ot.Kind() == vt.Kind() && ot.ConvertibleTo(vt) {
+		// 底层类型相同的命名类型，如time.Duration与int64
+		v.Set(ov.Convert(vt))
+	} else {
+		return fmt.Errorf("Inject value: bean %s type %s cannot assign to %s. ", name,
+			reflection.GetTypeName(ot), reflection.GetTypeName(vt))
+	}
+	recordDependency(c, o)
+	return nil
+}
+
 func (injector *defaultInjector) injectStruct(c bean.Container, name string, v reflect.Value) error {
 	vt := v.Type()
-	if name == "" {
+	name, opt := parseInjectName(name)
+	if name == "" && opt.qualifier == "" {
 		name = reflection.GetTypeName(vt)
 	}
 	o, ok := c.GetDefinition(name)
+	if !ok && opt.qualifier != "" {
+		var err error
+		o, err = selectCandidate(c, vt, opt.qualifier)
+		if err != nil {
+			return err
+		}
+		ok = true
+	}
 	if ok {
-		ov := o.Value()
+		ov := valueOf(c, o)
 		if vt.Kind() == reflect.Ptr {
 			v.Set(ov)
+			recordDependency(c, o)
+		} else if o.Type().AssignableTo(vt) {
+			// 注册为结构体值的对象，注入值的拷贝
+			v.Set(ov)
+			recordDependency(c, o)
 		} else {
 			// 只允许注入指针类型
 			err := fmt.Errorf("Inject struct: [%s] failed: value must be pointer. ", reflection.GetTypeName(vt))
@@ -298,11 +547,15 @@ func (injector *defaultInjector) injectStruct(c bean.Container, name string, v r
 		return nil
 	}
 
-	if injector.recursive {
-		return injector.injectStructFields(c, v)
-	} else {
-		return errors.New("Inject nothing, cannot find any instance of  " + reflection.GetTypeName(vt))
+	// 不存在对象时注入结构体中的字段（结构体指针需要配置OptSetRecursive）
+	if name == reflection.GetTypeName(vt) && isNestedField(c, "", vt, injector.tagName, injector.recursive) {
+		root := vt
+		if root.Kind() == reflect.Ptr {
+			root = root.Elem()
+		}
+		return injector.injectNested(c, root, v, "", map[reflect.Type]bool{})
 	}
+	return errors.New("Inject nothing, cannot find any instance of  " + reflection.GetTypeName(vt))
 }
 
 func OptSetLogger(v xlog.Logger) Opt {
@@ -317,6 +570,8 @@ func OptSetInjectTagName(v string) Opt {
 	}
 }
 
+// 配置使用tag标记、且容器中不存在对象的结构体指针字段是否创建新的对象并注入其中的字段
+// 结构体的值及匿名嵌入的结构体默认注入其中的字段，不需要配置
 func OptSetRecursive(recursive bool) Opt {
 	return func(injector *defaultInjector) {
 		injector.recursive = recursive
@@ -332,6 +587,13 @@ func OptSetActuator(kind reflect.Kind, actuator Actuator) Opt {
 	}
 }
 
+// 配置属性，用于解析注入名称中的配置属性占位符，如：${server.port:8080}
+func OptSetProperties(props fig.Properties) Opt {
+	return func(injector *defaultInjector) {
+		injector.props = props
+	}
+}
+
 // 配置监听器
 func OptSetListener(field string, listener Listener) Opt {
 	return func(injector *defaultInjector) {
@@ -350,9 +612,18 @@ func OptSetListenerManager(manager ListenerManager) Opt {
 	}
 }
 
+// 自动收集slice、map元素时，除可赋值的对象外仅转换底层类型相同的对象类型
+// 值类型的对象（如string、int、time.Duration）不做转换，避免被注入到无关的元素类型中
+func convertibleElem(ot, et reflect.Type) bool {
+	return !bean.IsValueKind(ot.Kind()) && ot.ConvertibleTo(et)
+}
+
 type sliceAppender struct {
+	c        bean.Container
 	v        reflect.Value
 	elemType reflect.Type
+	opt      injectOption
+	defs     []bean.Definition
 }
 
 func (s *sliceAppender) Set(value reflect.Value) error {
@@ -361,12 +632,23 @@ func (s *sliceAppender) Set(value reflect.Value) error {
 }
 
 func (s *sliceAppender) Scan(key string, value bean.Definition) bool {
+	if !s.opt.match(value) {
+		return true
+	}
+	// 同一个对象定义可能有多个名称（如注入时缓存的名称），仅注入一次
+	for _, d := range s.defs {
+		if d == value {
+			return true
+		}
+	}
 	ot := value.Type()
 	// interface
 	if ot.AssignableTo(s.elemType) {
-		s.v = reflect.Append(s.v, value.Value())
-	} else if ot.ConvertibleTo(s.elemType) {
-		s.v = reflect.Append(s.v, value.Value().Convert(s.elemType))
+		s.v = reflect.Append(s.v, valueOf(s.c, value))
+		s.defs = append(s.defs, value)
+	} else if convertibleElem(ot, s.elemType) {
+		s.v = reflect.Append(s.v, valueOf(s.c, value).Convert(s.elemType))
+		s.defs = append(s.defs, value)
 	}
 	//if s.elemType.Kind() == reflect.Interface {
 	//	if ot.Implements(s.elemType) {
@@ -385,8 +667,11 @@ func (s *sliceAppender) Scan(key string, value bean.Definition) bool {
 }
 
 type mapPutter struct {
+	c        bean.Container
 	v        reflect.Value
 	elemType reflect.Type
+	opt      injectOption
+	defs     []bean.Definition
 }
 
 func (s *mapPutter) Set(value reflect.Value) error {
@@ -395,12 +680,23 @@ func (s *mapPutter) Set(value reflect.Value) error {
 }
 
 func (s *mapPutter) Scan(key string, value bean.Definition) bool {
+	if !s.opt.match(value) {
+		return true
+	}
+	// 同一个对象定义可能有多个名称（如注入时缓存的名称），仅注入一次
+	for _, d := range s.defs {
+		if d == value {
+			return true
+		}
+	}
 	ot := value.Type()
 	// interface
 	if ot.AssignableTo(s.elemType) {
-		s.v.SetMapIndex(reflect.ValueOf(key), value.Value())
-	} else if ot.ConvertibleTo(s.elemType) {
-		s.v.SetMapIndex(reflect.ValueOf(key), value.Value().Convert(s.elemType))
+		s.v.SetMapIndex(reflect.ValueOf(key), valueOf(s.c, value))
+		s.defs = append(s.defs, value)
+	} else if convertibleElem(ot, s.elemType) {
+		s.v.SetMapIndex(reflect.ValueOf(key), valueOf(s.c, value).Convert(s.elemType))
+		s.defs = append(s.defs, value)
 	}
 	//if s.elemType.Kind() == reflect.Interface {
 	//	if ot.Implements(s.elemType) {
@@ -468,7 +764,16 @@ func (mgr *defaultListenerManager) AddListener(name string, listener Listener) {
 
 func (mgr *defaultListenerManager) ParseListener(tag string) (string, []Listener) {
 	strs := strings.Split(tag, ",")
-	opts := strs[1:]
+	name := strs[0]
+	opts := make([]string, 0, len(strs)-1)
+	for _, v := range strs[1:] {
+		// key=value形式的为注入选项，保留在名称中由Injector处理
+		if strings.Contains(v, "=") {
+			name = name + "," + v
+		} else {
+			opts = append(opts, v)
+		}
+	}
 	// default must be required
 	if len(opts) == 0 {
 		opts = []string{RequiredTagField}
@@ -482,5 +787,5 @@ func (mgr *defaultListenerManager) ParseListener(tag string) (string, []Listener
 		}
 	}
 
-	return strs[0], ret
+	return name, ret
 }
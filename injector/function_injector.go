@@ -46,7 +46,7 @@ func (invoker *defaultInjectInvoker) Invoke(ij Injector, container bean.Containe
 		if manager != nil {
 			name, listeners = manager.ParseListener(name)
 		}
-		err := ij.InjectValue(container, name, o)
+		err := ij.InjectValue(withInjectPoint(container, ParamPoint(invoker.FunctionName(), i)), name, o)
 		if err != nil {
 			err = fmt.Errorf("Inject function [%s] failed:error: %s\n", invoker.FunctionName(), err.Error())
 			for _, l := range listeners {
@@ -155,6 +155,35 @@ func (fi *defaultInjectFunctionHandler) InjectAllFunctions(container bean.Contai
 	return last
 }
 
+type containerInvoker struct {
+	FunctionInjectInvoker
+	container bean.Container
+}
+
+func (invoker *containerInvoker) Invoke(injector Injector, container bean.Container, manager ListenerManager) error {
+	return invoker.FunctionInjectInvoker.Invoke(injector, invoker.container, manager)
+}
+
+type containerRegistry struct {
+	registry  ContainerInjectFunctionRegistry
+	container bean.Container
+}
+
+func (r *containerRegistry) RegisterInjectFunction(function interface{}, names ...string) error {
+	return r.registry.RegisterInjectFunctionWithContainer(r.container, function, names...)
+}
+
+// 获得使用指定容器注入的方法注册器，如果registry不支持指定容器则直接返回registry
+func WithContainer(registry InjectFunctionRegistry, c bean.Container) InjectFunctionRegistry {
+	if v, ok := registry.(ContainerInjectFunctionRegistry); ok {
+		return &containerRegistry{
+			registry:  v,
+			container: c,
+		}
+	}
+	return registry
+}
+
 func create() FunctionInjectInvoker {
 	return &defaultInjectInvoker{}
 }
@@ -168,6 +197,19 @@ func (fi *defaultInjectFunctionHandler) RegisterInjectFunction(function interfac
 	return nil
 }
 
+// 注册注入方法，注入时使用指定的容器c代替InjectAllFunctions传入的容器
+func (fi *defaultInjectFunctionHandler) RegisterInjectFunctionWithContainer(c bean.Container, function interface{}, names ...string) error {
+	invoker := fi.creator()
+	if err := invoker.ResolveFunction(fi.injector, names[:], function); err != nil {
+		return err
+	}
+	fi.addInvoker(&containerInvoker{
+		FunctionInjectInvoker: invoker,
+		container:             c,
+	})
+	return nil
+}
+
 func (fi *defaultInjectFunctionHandler) addInvoker(invoker FunctionInjectInvoker) {
 	fi.locker.Lock()
 	defer fi.locker.Unlock()
@@ -175,21 +217,34 @@ func (fi *defaultInjectFunctionHandler) addInvoker(invoker FunctionInjectInvoker
 	fi.invokers = append(fi.invokers, invoker)
 }
 
-// FIXME: This isn't a elegant implementation (but works well).
+// 包装对象的构造方法（包括CustomBeanFactory的创建方法），将参数自动代理注入，变为无参数的构造方法
+// 注意：包装后的构造方法不传递创建链，构造方法之间的循环依赖无法检测，容器注册时应使用WrapBeanWithCreation
 func WrapBean(o interface{}, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
-	// 如果是CustomBeanFactory则需要将创建bean方法的参数自动代理注入，变为无参数仅返回的创建方法
+	return wrapBean(o, container, injector, manager, WrapBeanFactoryByNameFunc, WrapBeanFactoryFunc)
+}
+
+// 与WrapBean相同，但包装后的构造方法仅接收创建链（*bean.Creation），容器创建对象时沿创建链注入参数并检测循环依赖
+func WrapBeanWithCreation(o interface{}, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
+	return wrapBean(o, container, injector, manager, wrapBeanFactoryByNameFunc, wrapBeanFactoryFunc)
+}
+
+// FIXME: This isn't a elegant implementation (but works well).
+func wrapBean(o interface{}, container bean.Container, injector Injector, manager ListenerManager,
+	byName func(interface{}, []string, bean.Container, Injector, ListenerManager) (interface{}, error),
+	byType func(interface{}, bean.Container, Injector, ListenerManager) (interface{}, error)) (interface{}, error) {
+	// 如果是CustomBeanFactory则需要将创建bean方法的参数自动代理注入
 	if b, ok := o.(bean.CustomBeanFactory); ok {
 		fac := b.BeanFactory()
 		if reflect.TypeOf(fac).NumIn() > 0 {
 			names := b.InjectNames()
 			if len(names) > 0 {
-				f, err := WrapBeanFactoryByNameFunc(fac, names, container, injector, manager)
+				f, err := byName(fac, names, container, injector, manager)
 				if err != nil {
 					return nil, err
 				}
 				return bean.CloneCustomBeanFactory(b, f), nil
 			} else {
-				f, err := WrapBeanFactoryFunc(fac, container, injector, manager)
+				f, err := byType(fac, container, injector, manager)
 				if err != nil {
 					return nil, err
 				}
@@ -199,5 +254,5 @@ func WrapBean(o interface{}, container bean.Container, injector Injector, manage
 			return o, nil
 		}
 	}
-	return WrapBeanFactoryFunc(o, container, injector, manager)
+	return byType(o, container, injector, manager)
 }
@@ -23,13 +23,57 @@ import (
 	"reflect"
 )
 
+// 包装后的构造方法的参数，调用时传入当前的创建链
+var creationParams = []reflect.Type{bean.CreationType}
+
+func factoryReturns(ft reflect.Type) []reflect.Type {
+	ret := make([]reflect.Type, ft.NumOut())
+	for i := range ret {
+		ret[i] = ft.Out(i)
+	}
+	return ret
+}
+
+// 使用names注入构造方法的参数，包装为无参数的构造方法：func() T或者func() (T, error)
+// 注意：包装后的构造方法不传递创建链，构造方法之间的循环依赖无法检测，容器注册时应使用WrapBeanWithCreation
 func WrapBeanFactoryByNameFunc(o interface{}, names []string, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
+	f, err := wrapBeanFactoryByNameFunc(o, names, container, injector, manager)
+	if err != nil {
+		return f, err
+	}
+	return withoutCreation(f), nil
+}
+
+// 自动注入构造方法的参数，包装为无参数的构造方法：func() T或者func() (T, error)
+// 注意：包装后的构造方法不传递创建链，构造方法之间的循环依赖无法检测，容器注册时应使用WrapBeanWithCreation
+func WrapBeanFactoryFunc(o interface{}, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
+	f, err := wrapBeanFactoryFunc(o, container, injector, manager)
+	if err != nil {
+		return f, err
+	}
+	return withoutCreation(f), nil
+}
+
+// 将仅接收创建链的构造方法包装为无参数的构造方法，调用时创建链为nil，其他对象直接返回
+func withoutCreation(f interface{}) interface{} {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.In(0) != bean.CreationType {
+		return f
+	}
+	return reflect.MakeFunc(reflect.FuncOf(nil, factoryReturns(ft), false), func(args []reflect.Value) (results []reflect.Value) {
+		return fv.Call([]reflect.Value{reflect.Zero(bean.CreationType)})
+	}).Interface()
+}
+
+// 使用names注入构造方法的参数，包装为仅接收创建链（*bean.Creation）的构造方法
+func wrapBeanFactoryByNameFunc(o interface{}, names []string, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
 	ft := reflect.TypeOf(o)
 	if ft.Kind() != reflect.Func {
 		return o, nil
 	}
-	if ft.NumOut() != 1 {
-		return o, fmt.Errorf("Bean Factory function: %s without any return value ", ft.String())
+	if !bean.VerifyFactoryReturns(ft) {
+		return o, fmt.Errorf("Bean Factory function: %s must return 1 value or (value, error) ", ft.String())
 	}
 
 	rt := ft.Out(0)
@@ -41,13 +85,15 @@ func WrapBeanFactoryByNameFunc(o interface{}, names []string, container bean.Con
 		if pn != len(names) {
 			return o, fmt.Errorf("Bean Factory function: %s have %d params but with %d names, Not match ", ft.String(), pn, len(names))
 		}
-		retFv := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{rt}, false), func(args []reflect.Value) (results []reflect.Value) {
+		retFv := reflect.MakeFunc(reflect.FuncOf(creationParams, factoryReturns(ft), false), func(args []reflect.Value) (results []reflect.Value) {
+			// 沿创建链注入参数
+			c := WithCreation(container, args[0].Interface().(*bean.Creation))
 			fv := reflect.ValueOf(o)
 			values := make([]reflect.Value, pn)
 			for i := 0; i < pn; i++ {
 				o := reflect.New(ft.In(i)).Elem()
 				name, ls := manager.ParseListener(names[i])
-				err := injector.InjectValue(container, name, o)
+				err := injector.InjectValue(withInjectPoint(c, ParamPoint("", i)), name, o)
 				if err != nil {
 					err = fmt.Errorf("Inject function [%s] param %d [%s] failed:error: %s\n", ft.String(), i, o.Type().String(), err.Error())
 					for _, l := range ls {
@@ -64,13 +110,14 @@ func WrapBeanFactoryByNameFunc(o interface{}, names []string, container bean.Con
 	return o, nil
 }
 
-func WrapBeanFactoryFunc(o interface{}, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
+// 自动注入构造方法的参数，包装为仅接收创建链（*bean.Creation）的构造方法
+func wrapBeanFactoryFunc(o interface{}, container bean.Container, injector Injector, manager ListenerManager) (interface{}, error) {
 	ft := reflect.TypeOf(o)
 	if ft.Kind() != reflect.Func {
 		return o, nil
 	}
-	if ft.NumOut() != 1 {
-		return o, fmt.Errorf("Bean Factory function: %s without any return value ", ft.String())
+	if !bean.VerifyFactoryReturns(ft) {
+		return o, fmt.Errorf("Bean Factory function: %s must return 1 value or (value, error) ", ft.String())
 	}
 
 	rt := ft.Out(0)
@@ -79,13 +126,15 @@ func WrapBeanFactoryFunc(o interface{}, container bean.Container, injector Injec
 	}
 	pn := ft.NumIn()
 	if pn > 0 {
-		retFv := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{rt}, false), func(args []reflect.Value) (results []reflect.Value) {
+		retFv := reflect.MakeFunc(reflect.FuncOf(creationParams, factoryReturns(ft), false), func(args []reflect.Value) (results []reflect.Value) {
+			// 沿创建链注入参数
+			c := WithCreation(container, args[0].Interface().(*bean.Creation))
 			fv := reflect.ValueOf(o)
 			values := make([]reflect.Value, pn)
 			for i := 0; i < pn; i++ {
 				o := reflect.New(ft.In(i)).Elem()
 				_, ls := manager.ParseListener("")
-				err := injector.InjectValue(container, "", o)
+				err := injector.InjectValue(withInjectPoint(c, ParamPoint("", i)), "", o)
 				if err != nil {
 					err = fmt.Errorf("Inject function [%s] failed:error: %s\n", ft.Name(), err.Error())
 					for _, l := range ls {
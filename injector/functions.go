@@ -34,6 +34,12 @@ type InjectFunction interface {
 	RegisterFunction(registry InjectFunctionRegistry) error
 }
 
+// 支持指定注入容器的方法注册器，用于区分注入方法所属的对象
+type ContainerInjectFunctionRegistry interface {
+	// 注册注入方法，注入时使用指定的容器c代替InjectAllFunctions传入的容器
+	RegisterInjectFunctionWithContainer(c bean.Container, function interface{}, names ...string) error
+}
+
 type InjectFunctionHandler interface {
 	InjectFunctionRegistry
 
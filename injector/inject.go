@@ -17,6 +17,7 @@
 package injector
 
 import (
+	"github.com/xfali/fig"
 	"github.com/xfali/neve-core/bean"
 	"reflect"
 )
@@ -43,6 +44,8 @@ type Injector interface {
 	InjectValue(c bean.Container, name string, v reflect.Value) error
 }
 
+// 注入执行器
+// name为注入名称，可能包含key=value形式的注入选项（使用逗号分隔），如："name,qualifier=fast"
 type Actuator func(c bean.Container, name string, v reflect.Value) error
 
 // 注入监听器
@@ -57,6 +60,7 @@ type ListenerManager interface {
 	AddListener(name string, listener Listener)
 
 	// 从传入字串中解析注入名称和匹配监听器
+	// key=value形式的注入选项应保留在返回的名称中，如："name,qualifier=fast"
 	ParseListener(v string) (name string, listeners []Listener)
 }
 
@@ -65,3 +69,10 @@ type ListenerManagerSetter interface {
 	// 设置监听管理者
 	SetListenerManager(manager ListenerManager)
 }
+
+// 配置属性设置器
+// 设置后注入名称可以使用配置属性占位符${key}或者${key:default}，注入的值从配置属性中获取并转换为注入的类型
+type PropertiesSetter interface {
+	// 设置配置属性
+	SetProperties(props fig.Properties)
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+// 结构体（或者结构体指针）中是否包含使用tagName标记的字段，包括匿名嵌入结构体中的字段
+func hasInjectFields(t reflect.Type, tagName string) bool {
+	return hasInjectFieldsVisit(t, tagName, map[reflect.Type]bool{})
+}
+
+func hasInjectFieldsVisit(t reflect.Type, tagName string, visited map[reflect.Type]bool) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup(tagName); ok {
+			return true
+		}
+		if field.Anonymous && hasInjectFieldsVisit(field.Type, tagName, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// 使用tag标记的字段是否作为嵌套结构体注入其字段（而不是从容器中获取对象）：
+// 1、未指定名称及注入选项，且容器中不存在该类型的对象
+// 2、字段为包含需要注入字段的结构体，或者配置了OptSetRecursive时的结构体指针
+func isNestedField(c bean.Container, name string, t reflect.Type, tagName string, recursive bool) bool {
+	name, opt := parseInjectName(name)
+	if name != "" || opt.filtered() {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+	case reflect.Ptr:
+		if !recursive || t.Elem().Kind() != reflect.Struct {
+			return false
+		}
+	default:
+		return false
+	}
+	if !hasInjectFields(t, tagName) {
+		return false
+	}
+	_, ok := c.GetDefinition(reflection.GetTypeName(t))
+	return !ok
+}
+
+// 匿名嵌入的字段是否需要注入其字段：结构体，或者结构体指针（不为nil，或者包含需要注入的字段且可以设置新创建的对象）
+func isEmbeddedInjectable(field reflect.StructField, v reflect.Value, tagName string) bool {
+	if !field.Anonymous {
+		return false
+	}
+	switch field.Type.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Ptr:
+		if field.Type.Elem().Kind() != reflect.Struct {
+			return false
+		}
+		// 未导出类型的嵌入指针为nil时无法设置
+		return !v.IsNil() || (v.CanSet() && hasInjectFields(field.Type, tagName))
+	}
+	return false
+}
+
+// 注入嵌套结构体（或者结构体指针）中的字段，结构体指针为nil时创建新的对象
+// visiting为当前注入路径上的结构体类型，用于防止无限递归
+func (injector *defaultInjector) injectNested(c bean.Container, root reflect.Type, v reflect.Value, path string, visiting map[reflect.Type]bool) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if visiting[v.Type().Elem()] {
+				return fmt.Errorf("Inject failed: Field [%s: %s] error: recursive struct %s ",
+					reflection.GetTypeName(root), path, reflection.GetTypeName(v.Type().Elem()))
+			}
+			if !v.CanSet() {
+				return fmt.Errorf("Inject failed: Field [%s: %s] error: value cannot set ",
+					reflection.GetTypeName(root), path)
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	prefix := ""
+	if path != "" {
+		prefix = path + "."
+	}
+	return injector.injectFields(c, root, v, prefix, visiting)
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// 配置属性占位符：${key}或者${key:default}
+type placeholder struct {
+	key        string
+	def        string
+	hasDefault bool
+}
+
+// 解析注入名称中的配置属性占位符，不是占位符时返回false
+func parsePlaceholder(name string) (placeholder, bool) {
+	name, _ = parseInjectName(name)
+	name = strings.TrimSpace(name)
+	if !strings.HasPrefix(name, "${") || !strings.HasSuffix(name, "}") {
+		return placeholder{}, false
+	}
+	ret := placeholder{}
+	kv := strings.SplitN(name[2:len(name)-1], ":", 2)
+	ret.key = strings.TrimSpace(kv[0])
+	if len(kv) == 2 {
+		ret.def = kv[1]
+		ret.hasDefault = true
+	}
+	return ret, ret.key != ""
+}
+
+// 配置属性是否存在
+func hasProperty(props fig.Properties, key string) bool {
+	var v interface{}
+	return props.GetValue(key, &v) == nil && v != nil
+}
+
+// 将配置属性占位符的值注入到v：
+// 基础类型从属性的字符串值转换（time.Duration使用time.ParseDuration），其他类型使用配置的格式反序列化
+// 属性不存在时使用默认值，没有默认值时返回错误
+func (injector *defaultInjector) injectProperty(p placeholder, v reflect.Value) error {
+	if injector.props == nil {
+		return fmt.Errorf("Inject property %s failed: properties not set. ", p.key)
+	}
+	vt := v.Type()
+	if !hasProperty(injector.props, p.key) {
+		if !p.hasDefault {
+			return fmt.Errorf("Inject property %s failed: property not found. ", p.key)
+		}
+		return setPropertyString(v, p.def)
+	}
+	if isBasicKind(vt.Kind()) {
+		return setPropertyString(v, injector.props.Get(p.key, p.def))
+	}
+	pv := reflect.New(vt)
+	err := injector.props.GetValue(p.key, pv.Interface())
+	if err != nil {
+		return fmt.Errorf("Inject property %s failed: %v ", p.key, err)
+	}
+	v.Set(pv.Elem())
+	return nil
+}
+
+func isBasicKind(kind reflect.Kind) bool {
+	for _, k := range basicKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// 将字符串转换为v的类型并设置
+func setPropertyString(v reflect.Value, s string) error {
+	vt := v.Type()
+	s = strings.TrimSpace(s)
+	var err error
+	switch vt.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if vt == durationType {
+			var d time.Duration
+			d, err = time.ParseDuration(s)
+			i = int64(d)
+		} else {
+			i, err = strconv.ParseInt(s, 10, vt.Bits())
+		}
+		if err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, vt.Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, vt.Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		err = errors.New("type not support")
+	}
+	if err != nil {
+		return fmt.Errorf("Property value %s cannot convert to %s: %v ", s, reflection.GetTypeName(vt), err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+var emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// 获得provider提供的对象类型：bean.Provider为interface{}，func() T及func() (T, error)为T
+// t不是provider类型时返回false
+func providerTarget(t reflect.Type) (reflect.Type, bool) {
+	if t == bean.ProviderType {
+		return emptyInterfaceType, true
+	}
+	if t.Kind() != reflect.Func || t.NumIn() != 0 || !bean.VerifyFactoryReturns(t) {
+		return nil, false
+	}
+	return t.Out(0), true
+}
+
+// 查找provider提供的对象定义，仅查找对象定义，不会创建对象
+// name不为空时先按名称查找，未指定名称及qualifier时使用类型的默认名称，不存在时按qualifier或者类型自动匹配
+func resolveProvider(c bean.Container, name string, opt injectOption, t reflect.Type) (bean.Definition, error) {
+	if t == emptyInterfaceType && name == "" && opt.qualifier == "" {
+		return nil, errors.New("Provider inject need bean name or qualifier. ")
+	}
+	if name == "" && opt.qualifier == "" {
+		name = reflection.GetTypeName(t)
+	}
+	if name != "" {
+		if d, ok := c.GetDefinition(name); ok {
+			if !d.Type().AssignableTo(t) {
+				return nil, fmt.Errorf("Provider: bean %s type %s cannot assign to %s. ", name,
+					reflection.GetTypeName(d.Type()), reflection.GetTypeName(t))
+			}
+			return d, nil
+		}
+		if opt.qualifier == "" && t == emptyInterfaceType {
+			return nil, fmt.Errorf("Provider: bean %s not found. ", name)
+		}
+	}
+	return selectCandidate(c, t, opt.qualifier)
+}
+
+// 注入时创建的对象提供者，每次获取时从容器中重新查找对象定义并获取值
+type provider struct {
+	c    bean.Container
+	name string
+	opt  injectOption
+	t    reflect.Type
+}
+
+func (p *provider) Get() (interface{}, error) {
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func (p *provider) value() (v reflect.Value, err error) {
+	d, err := resolveProvider(p.c, p.name, p.opt, p.t)
+	if err != nil {
+		return v, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*bean.CreateBeanError)
+			if !ok {
+				panic(r)
+			}
+			err = e
+		}
+	}()
+	v = d.Value()
+	if !v.IsValid() {
+		return v, fmt.Errorf("Provider: bean %s value is invalid. ", d.Name())
+	}
+	return v, nil
+}
+
+// 创建func() T或者func() (T, error)类型的方法，调用时通过provider获取对象
+// func() T获取失败时panic
+func (p *provider) makeFunc(ft reflect.Type) reflect.Value {
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		ret := reflect.New(ft.Out(0)).Elem()
+		v, err := p.value()
+		if err == nil {
+			ret.Set(v)
+		}
+		if ft.NumOut() == 1 {
+			if err != nil {
+				panic(err)
+			}
+			return []reflect.Value{ret}
+		}
+		errV := reflect.New(bean.ErrorType).Elem()
+		if err != nil {
+			errV.Set(reflect.ValueOf(err))
+		}
+		return []reflect.Value{ret, errV}
+	})
+}
+
+// 注入bean.Provider、func() T或者func() (T, error)类型的值，注入时不获取对象，在调用时才获取
+// 注入后检查对象定义是否可以找到，找不到时仍然完成注入（可以在之后动态注册）并返回错误
+// provider不会记录依赖关系，因此不影响对象的初始化顺序
+func (injector *defaultInjector) injectProvider(c bean.Container, name string, v reflect.Value) error {
+	vt := v.Type()
+	t, ok := providerTarget(vt)
+	if !ok {
+		return errors.New("Cannot inject this kind: " + reflection.GetTypeName(vt))
+	}
+	name, opt := parseInjectName(name)
+	p := &provider{
+		c:    c,
+		name: name,
+		opt:  opt,
+		t:    t,
+	}
+	if vt == bean.ProviderType {
+		v.Set(reflect.ValueOf(p))
+	} else {
+		v.Set(p.makeFunc(vt))
+	}
+	_, err := resolveProvider(c, name, opt, t)
+	return err
+}
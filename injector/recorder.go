@@ -0,0 +1,202 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"reflect"
+)
+
+// 注入点，描述对象被注入的位置
+type InjectPoint struct {
+	// 注入的字段名称，方法参数注入时为空
+	Field string
+	// 注入的方法名称，字段注入及构造方法参数注入时为空
+	Function string
+	// 注入的方法参数序号（从0开始），字段注入时为-1
+	Index int
+}
+
+func (p InjectPoint) String() string {
+	if p.Index < 0 {
+		// 方法对对象的依赖（如配置类的bean方法）
+		if p.Field == "" && p.Function != "" {
+			return p.Function
+		}
+		return "field:" + p.Field
+	}
+	if p.Function == "" {
+		return fmt.Sprintf("param:%d", p.Index)
+	}
+	return fmt.Sprintf("%s#%d", p.Function, p.Index)
+}
+
+func FieldPoint(field string) InjectPoint {
+	return InjectPoint{
+		Field: field,
+		Index: -1,
+	}
+}
+
+func ParamPoint(function string, index int) InjectPoint {
+	return InjectPoint{
+		Function: function,
+		Index:    index,
+	}
+}
+
+// 依赖记录器
+// 如果注入时传入的容器实现了该接口，则注入成功后回调注入点及被注入的对象定义
+type DependencyRecorder interface {
+	RecordDependency(point InjectPoint, d bean.Definition)
+}
+
+// 带注入点及创建链的容器，用于记录依赖及检测循环依赖
+type pointContainer struct {
+	bean.Container
+	recorder DependencyRecorder
+	point    InjectPoint
+	creation *bean.Creation
+}
+
+// 为容器绑定注入点，容器未实现DependencyRecorder且没有创建链时直接返回原容器
+func withInjectPoint(c bean.Container, point InjectPoint) bean.Container {
+	switch v := c.(type) {
+	case *pointContainer:
+		return &pointContainer{
+			Container: v.Container,
+			recorder:  v.recorder,
+			point:     point,
+			creation:  v.creation,
+		}
+	case DependencyRecorder:
+		return &pointContainer{
+			Container: c,
+			recorder:  v,
+			point:     point,
+		}
+	}
+	return c
+}
+
+// 为容器绑定创建链，使用返回的容器注入时沿调用路径传递创建链，详情查看bean.Creation
+func WithCreation(c bean.Container, chain *bean.Creation) bean.Container {
+	if chain == nil {
+		return c
+	}
+	if v, ok := c.(*pointContainer); ok {
+		ret := *v
+		ret.creation = chain
+		return &ret
+	}
+	recorder, _ := c.(DependencyRecorder)
+	return &pointContainer{
+		Container: c,
+		recorder:  recorder,
+		creation:  chain,
+	}
+}
+
+// 获得容器绑定的创建链
+func creationOf(c bean.Container) *bean.Creation {
+	if v, ok := c.(*pointContainer); ok {
+		return v.creation
+	}
+	return nil
+}
+
+// 沿容器绑定的创建链获得对象定义的值
+func valueOf(c bean.Container, d bean.Definition) reflect.Value {
+	return bean.ValueIn(d, creationOf(c))
+}
+
+// 以下方法转发到被包装的容器，保留容器实现的可选接口
+
+func (c *pointContainer) Alias(name, alias string) error {
+	return bean.Alias(c.Container, name, alias)
+}
+
+func (c *pointContainer) Replace(name string, o interface{}, opts ...bean.RegisterOpt) (bean.Definition, error) {
+	return bean.Replace(c.Container, name, o, opts...)
+}
+
+func (c *pointContainer) Remove(name string) (bean.Definition, bool) {
+	return bean.Remove(c.Container, name)
+}
+
+func (c *pointContainer) FindByType(t reflect.Type) []bean.Definition {
+	return bean.FindByType(c.Container, t)
+}
+
+func (c *pointContainer) FindAssignable(t reflect.Type) []bean.Definition {
+	return bean.FindAssignable(c.Container, t)
+}
+
+func recordDependency(c bean.Container, d bean.Definition) {
+	if v, ok := c.(*pointContainer); ok && v.recorder != nil && d != nil {
+		v.recorder.RecordDependency(v.point, d)
+	}
+}
+
+// 如果是自动注入缓存的对象定义，则记录所有组成该缓存的对象定义
+func recordDependencies(c bean.Container, d bean.Definition) {
+	if v, ok := d.(*cachedDefinition); ok {
+		for _, s := range v.sources {
+			recordDependency(c, s)
+		}
+		return
+	}
+	recordDependency(c, d)
+}
+
+// 自动注入slice、map时缓存到容器中的对象定义，保存组成该缓存的对象定义
+type cachedDefinition struct {
+	bean.Definition
+	sources []bean.Definition
+}
+
+func newCachedDefinition(o interface{}, sources []bean.Definition) (bean.Definition, error) {
+	d, err := bean.CreateBeanDefinition(o)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedDefinition{
+		Definition: d,
+		sources:    sources,
+	}, nil
+}
+
+// 移除容器中由对象定义d组成的缓存（自动注入slice、map时缓存到容器中的对象定义）
+// 对象定义的值发生变化（如被BeanPostProcessor替换）时调用，之后的注入将重新生成缓存
+func RemoveCaches(c bean.Container, d bean.Definition) {
+	var names []string
+	c.Scan(func(key string, value bean.Definition) bool {
+		if v, ok := value.(*cachedDefinition); ok {
+			for _, s := range v.sources {
+				if s == d {
+					names = append(names, key)
+					break
+				}
+			}
+		}
+		return true
+	})
+	for _, name := range names {
+		bean.Remove(c, name)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+// 按照注入时的规则从容器中查找类型为t的对象定义：
+// 1、name不为空时仅按名称查找（包括别名），对象类型必须可以赋值给t
+// 2、name为空时接口类型优先选择primary对象，然后按类型默认名称查找，不存在时自动匹配可以赋值给t的对象（规则同自动注入，多个候选对象时选择primary对象）
+func Resolve(c bean.Container, name string, t reflect.Type) (bean.Definition, error) {
+	if name != "" {
+		d, ok := c.GetDefinition(name)
+		if !ok {
+			return nil, fmt.Errorf("Bean %s not found. ", name)
+		}
+		if !d.Type().AssignableTo(t) {
+			return nil, fmt.Errorf("Bean %s type %s cannot assign to %s. ", name,
+				reflection.GetTypeName(d.Type()), reflection.GetTypeName(t))
+		}
+		return d, nil
+	}
+
+	if t.Kind() == reflect.Interface {
+		d, err := selectPrimary(c, t)
+		if err != nil || d != nil {
+			return d, err
+		}
+	}
+	if d, ok := c.GetDefinition(reflection.GetTypeName(t)); ok && d.Type().AssignableTo(t) {
+		return d, nil
+	}
+	return selectCandidate(c, t, "")
+}
+
+// 查找容器中所有可以赋值给t的对象定义（包括指定名称注册的对象），同一个对象定义仅返回一次
+func ResolveAll(c bean.Container, t reflect.Type) []bean.Definition {
+	return bean.FindAssignable(c, t)
+}
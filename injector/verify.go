@@ -0,0 +1,215 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+)
+
+type VerifyStatus int
+
+const (
+	// 依赖可以注入
+	VerifyResolved VerifyStatus = iota
+	// 找不到依赖的对象
+	VerifyUnresolved
+	// 匹配到多个候选对象且无法确定（未配置或者配置了多个primary）
+	VerifyAmbiguous
+	// 找不到依赖的对象，但配置为可选（未配置required，如omiterror）
+	VerifyOptionalMissing
+)
+
+func (s VerifyStatus) String() string {
+	switch s {
+	case VerifyResolved:
+		return "RESOLVED"
+	case VerifyUnresolved:
+		return "UNRESOLVED"
+	case VerifyAmbiguous:
+		return "AMBIGUOUS"
+	case VerifyOptionalMissing:
+		return "OPTIONAL_MISSING"
+	}
+	return "UNKNOWN"
+}
+
+// 单个注入点的检查结果
+type VerifyResult struct {
+	// 注入点
+	Point InjectPoint
+	// 注入的类型
+	Type reflect.Type
+	// 注入名称（包含注入选项），自动注入时为空
+	Name string
+	// 检查结果
+	Status VerifyStatus
+	// 无法注入的原因，可以注入时为nil
+	Err error
+}
+
+// 支持静态检查依赖的Injector，检查时不会创建对象、注入或者调用生命周期方法
+type Verifier interface {
+	// 检查对象o中所有需要注入的字段
+	VerifyFields(c bean.Container, o interface{}) []VerifyResult
+
+	// 检查注入点point上类型为t的值，tag为注入名称（可能包含注入选项及监听器，如"name,omiterror"）
+	VerifyValue(c bean.Container, point InjectPoint, tag string, t reflect.Type) VerifyResult
+}
+
+func (injector *defaultInjector) VerifyFields(c bean.Container, o interface{}) []VerifyResult {
+	t := reflect.TypeOf(o)
+	if t == nil {
+		return nil
+	}
+	var ret []VerifyResult
+	visitInjectFields(c, t, injector.tagName, injector.lm, injector.recursive, func(path, tagAll string, ft reflect.Type) {
+		ret = append(ret, injector.VerifyValue(c, FieldPoint(path), tagAll, ft))
+	})
+	return ret
+}
+
+func (injector *defaultInjector) VerifyValue(c bean.Container, point InjectPoint, tag string, t reflect.Type) VerifyResult {
+	name, listeners := injector.lm.ParseListener(tag)
+	ret := VerifyResult{
+		Point:  point,
+		Type:   t,
+		Name:   name,
+		Status: VerifyResolved,
+	}
+	if p, ok := parsePlaceholder(name); ok {
+		if p.hasDefault || (injector.props != nil && hasProperty(injector.props, p.key)) {
+			return ret
+		}
+		ret.Err = fmt.Errorf("Property %s not found. ", p.key)
+		ret.Status = missingStatus(listeners)
+		return ret
+	}
+	kt := t
+	if kt.Kind() == reflect.Ptr {
+		kt = kt.Elem()
+	}
+	switch kt.Kind() {
+	case reflect.Interface, reflect.Slice, reflect.Map, reflect.Func:
+	default:
+		// 自定义的注入执行器无法静态检查
+		if !bean.IsValueKind(kt.Kind()) && injector.actuators[kt.Kind()] != nil {
+			return ret
+		}
+	}
+
+	_, err := resolveStatic(c, name, t)
+	if err == nil {
+		return ret
+	}
+	ret.Err = err
+	var ae *ambiguousError
+	if errors.As(err, &ae) {
+		ret.Status = VerifyAmbiguous
+		return ret
+	}
+	ret.Status = missingStatus(listeners)
+	return ret
+}
+
+// 找不到依赖时的检查结果：配置了RequiredListener时为VerifyUnresolved，否则为VerifyOptionalMissing
+func missingStatus(listeners []Listener) VerifyStatus {
+	for _, l := range listeners {
+		if _, ok := l.(*RequiredListener); ok {
+			return VerifyUnresolved
+		}
+	}
+	return VerifyOptionalMissing
+}
+
+// 检查构造方法（或者CustomBeanFactory）的参数，不会调用构造方法
+func VerifyFactory(v Verifier, c bean.Container, o interface{}) []VerifyResult {
+	var names []string
+	if b, ok := o.(bean.CustomBeanFactory); ok {
+		o = b.BeanFactory()
+		names = b.InjectNames()
+	}
+	ft := reflect.TypeOf(o)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil
+	}
+	ret := make([]VerifyResult, 0, ft.NumIn())
+	for i := 0; i < ft.NumIn(); i++ {
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		ret = append(ret, v.VerifyValue(c, ParamPoint("", i), name, ft.In(i)))
+	}
+	return ret
+}
+
+// 检查对象通过InjectFunction注册的注入方法的参数，仅调用RegisterFunction获得注入方法，不会调用注入方法
+func VerifyInjectFunction(v Verifier, c bean.Container, f InjectFunction) ([]VerifyResult, error) {
+	r := &verifyRegistry{}
+	err := f.RegisterFunction(r)
+	if err != nil {
+		return nil, err
+	}
+	var ret []VerifyResult
+	for _, fn := range r.functions {
+		ft := reflect.TypeOf(fn.function)
+		if ft == nil || ft.Kind() != reflect.Func {
+			return ret, errors.New("Param is not a function. ")
+		}
+		funcName := reflection.GetTypeName(ft)
+		if funcName == "" {
+			funcName = "func"
+		}
+		names := fn.names
+		if len(names) > 0 {
+			names = formatNames(append([]string{}, names...), ft.NumIn())
+		}
+		for i := 0; i < ft.NumIn(); i++ {
+			name := ""
+			if i < len(names) {
+				name = names[i]
+			}
+			ret = append(ret, v.VerifyValue(c, ParamPoint(funcName, i), name, ft.In(i)))
+		}
+	}
+	return ret, nil
+}
+
+type registeredFunction struct {
+	function interface{}
+	names    []string
+}
+
+// 记录注册的注入方法，用于检查
+type verifyRegistry struct {
+	functions []registeredFunction
+}
+
+func (r *verifyRegistry) RegisterInjectFunction(function interface{}, names ...string) error {
+	if function == nil {
+		return errors.New("Inject function is nil. ")
+	}
+	r.functions = append(r.functions, registeredFunction{
+		function: function,
+		names:    names,
+	})
+	return nil
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+// 对象初始化前后的处理器，可以包装或者替换单例对象（如添加监控、链路追踪、缓存等装饰器）
+// 在对象注入、分类之后，按注册顺序在BeanAfterSet前后调用。为了支持多协程处理，实现应线程安全。
+// 返回的对象将替换依赖方注入及获取到的对象，其类型必须可以赋值给对象定义的类型（如注册为接口类型的构造方法），
+// 初始化及销毁等生命周期方法仍然作用于原对象。
+// 注意：处理器本身以及非单例的对象不会被处理
+type BeanPostProcessor interface {
+	// 在BeanAfterSet之前调用
+	// name: 对象注册的名称，o: 当前对象（可能已被之前的处理器替换）
+	// return: 处理后的对象（不替换时返回o），返回错误时停止处理
+	BeforeInit(name string, o interface{}) (interface{}, error)
+
+	// 在BeanAfterSet之后调用，参数及返回值同BeforeInit
+	AfterInit(name string, o interface{}) (interface{}, error)
+}
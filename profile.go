@@ -0,0 +1,59 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neve
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 获得profile配置文件路径，如：application.yaml -> application-dev.yaml
+func ProfileConfigPath(configPath, profile string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "-" + profile + ext
+}
+
+// 加载激活的profile配置文件，并合并到基础配置之上
+// 后激活的profile优先级更高，profile配置文件不存在时忽略
+func LoadProfileProperties(configPath string, base fig.Properties) (fig.Properties, error) {
+	profiles := appcontext.ActiveProfiles(base)
+	if len(profiles) == 0 {
+		return base, nil
+	}
+	props := make([]fig.Properties, 0, len(profiles)+1)
+	for i := len(profiles) - 1; i >= 0; i-- {
+		path := ProfileConfigPath(configPath, profiles[i])
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		prop, err := fig.LoadYamlFile(path)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, prop)
+	}
+	if len(props) == 0 {
+		return base, nil
+	}
+	return fig.MergeProperties(append(props, base)...), nil
+}
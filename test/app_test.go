@@ -469,18 +469,13 @@ func TestAppCircleDependency(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	go func() {
-		defer func() {
-			o := recover()
-			if o == nil {
-				t.Fatal("Must be panic Circular dependency")
-			} else {
-				t.Log(o)
-			}
-		}()
-		app.Run()
-	}()
-	time.Sleep(2 * time.Second)
+	// 启动时静态检测到循环依赖，返回错误而不是panic
+	err = app.Run()
+	var cerr *appcontext.CircularDependencyError
+	if !errors.As(err, &cerr) {
+		t.Fatal("Must be Circular dependency error, but get ", err)
+	}
+	t.Log(err)
 	if o.A != nil {
 		t.Fatal("expect nil but get ", o.A)
 	}
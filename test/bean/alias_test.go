@@ -0,0 +1,65 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	c := bean.NewContainer()
+	o := &aImpl{v: "db"}
+	if err := c.RegisterByName("db", o, bean.SetAliases("primaryDB")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bean.Alias(c, "db", "legacyDB"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"db", "primaryDB", "legacyDB"} {
+		v, ok := c.Get(name)
+		if !ok || v != o {
+			t.Fatal("expect get bean by ", name)
+		}
+	}
+
+	if err := bean.Alias(c, "db", "primaryDB"); err == nil {
+		t.Fatal("expect alias exists error")
+	}
+	if err := bean.Alias(c, "notExist", "x"); err == nil {
+		t.Fatal("expect bean not found error")
+	}
+	if err := c.RegisterByName("other", &aImpl{}, bean.SetAliases("legacyDB")); err == nil {
+		t.Fatal("expect alias conflict error")
+	}
+
+	count := 0
+	c.Scan(func(key string, value bean.Definition) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Fatal("expect scan 1 definition but get ", count)
+	}
+
+	bean.Remove(c, "primaryDB")
+	for _, name := range []string{"db", "primaryDB", "legacyDB"} {
+		if _, ok := c.Get(name); ok {
+			t.Fatal("expect removed ", name)
+		}
+	}
+}
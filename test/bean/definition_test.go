@@ -0,0 +1,58 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"reflect"
+	"testing"
+)
+
+// 仅实现bean.Definition的自定义对象定义
+type chanDefinition struct {
+	o interface{}
+}
+
+func (d *chanDefinition) Type() reflect.Type                       { return reflect.TypeOf(d.o) }
+func (d *chanDefinition) Name() string                             { return "chan" }
+func (d *chanDefinition) Value() reflect.Value                     { return reflect.ValueOf(d.o) }
+func (d *chanDefinition) Interface() interface{}                   { return d.o }
+func (d *chanDefinition) IsObject() bool                           { return false }
+func (d *chanDefinition) AfterSet() error                          { return nil }
+func (d *chanDefinition) Destroy() error                           { return nil }
+func (d *chanDefinition) Classify(c bean.Classifier) (bool, error) { return c.Classify(d.o) }
+
+func TestCustomDefinition(t *testing.T) {
+	bean.RegisterBeanDefinitionCreator(reflect.Chan, func(o interface{}) (bean.Definition, error) {
+		return &chanDefinition{o: o}, nil
+	})
+	c := bean.NewContainer()
+	if err := c.Register(make(chan int), bean.SetQualifier("q"), bean.SetLabels("kind=chan")); err != nil {
+		t.Fatal(err)
+	}
+	d, ok := c.GetDefinition("chan")
+	if !ok {
+		t.Fatal("expect custom definition registered")
+	}
+	if bean.ScopeOf(d) != bean.Singleton || bean.IsPrimary(d) || bean.IsLazy(d) ||
+		len(bean.QualifiersOf(d)) != 0 || bean.LabelsOf(d) != nil || bean.HasQualifier(d, "q") {
+		t.Fatal("expect default meta for custom definition")
+	}
+	if _, ok := bean.AttributeOf(d, "any"); ok {
+		t.Fatal("expect no attribute")
+	}
+}
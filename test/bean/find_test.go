@@ -0,0 +1,110 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type closerImpl struct {
+	v string
+}
+
+func (c *closerImpl) Close() error {
+	return nil
+}
+
+func TestFind(t *testing.T) {
+	closerType := reflect.TypeOf((*io.Closer)(nil)).Elem()
+	c := bean.NewContainer()
+	a := &aImpl{v: "a"}
+	c1 := &closerImpl{v: "c1"}
+	c2 := &closerImpl{v: "c2"}
+	c.Register(a)
+	c.RegisterByName("c1", c1, bean.SetAliases("closer1"))
+	c.RegisterByName("c2", c2, bean.SetOrder(-1))
+	// 缓存的名称不重复返回
+	d, _ := c.GetDefinition("c1")
+	c.PutDefinition("cached", d)
+
+	defs := bean.FindByType(c, reflect.TypeOf(c1))
+	if len(defs) != 2 || defs[0].Interface() != c2 || defs[1].Interface() != c1 {
+		t.Fatal("expect [c2, c1] but get ", defs)
+	}
+	if defs := bean.FindAssignable(c, closerType); len(defs) != 2 {
+		t.Fatal("expect 2 closer but get ", defs)
+	}
+	if defs := bean.FindByType(c, closerType); len(defs) != 0 {
+		t.Fatal("expect no bean of interface type but get ", defs)
+	}
+
+	t.Run("remove and replace", func(t *testing.T) {
+		bean.Remove(c, "c2")
+		bean.Replace(c, "closer1", &aImpl{v: "c1"})
+		if defs := bean.FindAssignable(c, closerType); len(defs) != 0 {
+			t.Fatal("expect empty but get ", defs)
+		}
+		if defs := bean.FindByType(c, reflect.TypeOf(a)); len(defs) != 2 {
+			t.Fatal("expect 2 aImpl but get ", defs)
+		}
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		child := bean.NewContainer(bean.OptContainerParent(c))
+		child.Register(&aImpl{v: "child"})
+		// 父容器中的对象定义缓存到子容器
+		d, _ := child.GetDefinition("c1")
+		child.PutDefinition("fromParent", d)
+		defs := bean.FindByType(child, reflect.TypeOf(a))
+		if len(defs) != 3 || defs[0].Interface().(*aImpl).v != "child" {
+			t.Fatal("expect child bean first but get ", defs)
+		}
+	})
+}
+
+// 仅实现bean.Container接口的自定义容器
+type basicContainer struct {
+	bean.Container
+}
+
+func TestFindBasicContainer(t *testing.T) {
+	closerType := reflect.TypeOf((*io.Closer)(nil)).Elem()
+	c := &basicContainer{Container: bean.NewContainer()}
+	c.Register(&aImpl{v: "a"})
+	c.RegisterByName("c1", &closerImpl{v: "c1"})
+	d, _ := c.GetDefinition("c1")
+	c.PutDefinition("cached", d)
+
+	if defs := bean.FindAssignable(c, closerType); len(defs) != 1 || defs[0] != d {
+		t.Fatal("expect 1 closer but get ", defs)
+	}
+	if defs := bean.FindByType(c, reflect.TypeOf(&aImpl{})); len(defs) != 1 {
+		t.Fatal("expect 1 aImpl but get ", defs)
+	}
+	if err := bean.Alias(c, "c1", "closer1"); err == nil {
+		t.Fatal("expect alias not support error")
+	}
+	if _, err := bean.Replace(c, "c1", &closerImpl{v: "c2"}); err == nil {
+		t.Fatal("expect replace not support error")
+	}
+	if _, ok := bean.Remove(c, "c1"); ok {
+		t.Fatal("expect remove not support")
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	c := bean.NewContainer()
+	first := &aImpl{v: "first"}
+	c.RegisterByName("first", first, bean.SetOrder(-1))
+	old := &aImpl{v: "old"}
+	if err := c.RegisterByName("db", old, bean.SetAliases("primaryDB"), bean.SetOrder(1)); err != nil {
+		t.Fatal(err)
+	}
+	// 缓存名称
+	d, _ := c.GetDefinition("db")
+	c.PutDefinition("cache", d)
+
+	n := &aImpl{v: "new"}
+	oldDef, err := bean.Replace(c, "primaryDB", n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldDef.Interface() != old {
+		t.Fatal("expect old definition")
+	}
+	for _, name := range []string{"db", "primaryDB", "cache"} {
+		if v, _ := c.Get(name); v != n {
+			t.Fatal("expect new bean by ", name)
+		}
+	}
+	var keys []string
+	c.Scan(func(key string, value bean.Definition) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 3 || keys[0] != "first" || keys[len(keys)-1] != "db" {
+		t.Fatal("expect order kept but get ", keys)
+	}
+
+	if _, err := bean.Replace(c, "notExist", n); err == nil {
+		t.Fatal("expect not found error")
+	}
+}
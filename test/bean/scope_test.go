@@ -0,0 +1,197 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type aImpl struct {
+	v string
+}
+
+type bImpl struct {
+	a *aImpl
+}
+
+func TestScope(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		c := bean.NewContainer()
+		c.Register(&aImpl{v: "a"})
+		c.RegisterByName("f", func() *aImpl {
+			return &aImpl{v: "f"}
+		})
+
+		d, _ := c.GetDefinition("f")
+		if bean.ScopeOf(d) != bean.Prototype {
+			t.Fatal("expect prototype but get ", bean.ScopeOf(d))
+		}
+		v1, _ := c.Get("f")
+		v2, _ := c.Get("f")
+		if v1 == v2 {
+			t.Fatal("function bean must return new instance")
+		}
+
+		o, _ := c.Get(d.Name())
+		d, _ = c.GetDefinition(d.Name())
+		if bean.ScopeOf(d) != bean.Singleton {
+			t.Fatal("expect singleton but get ", bean.ScopeOf(d))
+		}
+		o2, _ := c.Get(d.Name())
+		if o != o2 {
+			t.Fatal("pointer bean must be singleton")
+		}
+	})
+
+	t.Run("function singleton", func(t *testing.T) {
+		c := bean.NewContainer()
+		count := 0
+		c.RegisterByName("f", func() *aImpl {
+			count++
+			return &aImpl{v: "f"}
+		}, bean.SetScope(bean.Singleton))
+		if count != 0 {
+			t.Fatal("singleton must be created lazily")
+		}
+		v1, _ := c.Get("f")
+		v2, _ := c.Get("f")
+		if v1 != v2 || count != 1 {
+			t.Fatal("expect same instance but get ", v1, v2)
+		}
+	})
+
+	t.Run("function singleton concurrent", func(t *testing.T) {
+		c := bean.NewContainer()
+		var count int32
+		c.RegisterByName("f", func() *aImpl {
+			atomic.AddInt32(&count, 1)
+			time.Sleep(10 * time.Millisecond)
+			return &aImpl{v: "f"}
+		}, bean.SetScope(bean.Singleton))
+		c.RegisterByName("p", func() *aImpl {
+			time.Sleep(10 * time.Millisecond)
+			return &aImpl{v: "p"}
+		})
+
+		values := make([]interface{}, 8)
+		wg := sync.WaitGroup{}
+		for i := range values {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				values[i], _ = c.Get("f")
+				// prototype可以并发创建
+				c.Get("p")
+			}(i)
+		}
+		wg.Wait()
+		for _, v := range values {
+			if v == nil || v != values[0] {
+				t.Fatal("expect same instance but get ", values)
+			}
+		}
+		if count != 1 {
+			t.Fatal("expect create once but get ", count)
+		}
+	})
+
+	t.Run("function circular", func(t *testing.T) {
+		c := bean.NewContainer()
+		i := injector.New()
+		lm := injector.NewListenerManager()
+		fa, _ := injector.WrapBeanWithCreation(func(b *bImpl) *aImpl {
+			return &aImpl{v: "a"}
+		}, c, i, lm)
+		fb, _ := injector.WrapBeanWithCreation(func(a *aImpl) *bImpl {
+			return &bImpl{a: a}
+		}, c, i, lm)
+		c.Register(fa, bean.SetScope(bean.Singleton))
+		c.Register(fb, bean.SetScope(bean.Singleton))
+		defer func() {
+			r := recover()
+			if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "Circular dependency") {
+				t.Fatal("expect circular dependency but get ", r)
+			}
+		}()
+		c.Get(reflection.GetTypeName(reflect.TypeOf(&aImpl{})))
+	})
+
+	t.Run("pointer prototype", func(t *testing.T) {
+		c := bean.NewContainer()
+		o := &aImpl{v: "a"}
+		c.RegisterByName("a", o, bean.SetScope(bean.Prototype))
+		v1, _ := c.Get("a")
+		v2, _ := c.Get("a")
+		if v1 == v2 || v1 == o {
+			t.Fatal("expect new instance")
+		}
+		if v1.(*aImpl).v != "a" || v2.(*aImpl).v != "a" {
+			t.Fatal("expect copy of a")
+		}
+	})
+
+	t.Run("slice prototype", func(t *testing.T) {
+		c := bean.NewContainer()
+		c.RegisterByName("s", []int{1, 2}, bean.SetScope(bean.Prototype))
+		v1, _ := c.Get("s")
+		v1.([]int)[0] = 3
+		v2, _ := c.Get("s")
+		if v2.([]int)[0] != 1 {
+			t.Fatal("expect 1 but get ", v2.([]int)[0])
+		}
+	})
+
+	t.Run("function prototype untracked", func(t *testing.T) {
+		d, err := bean.CreateBeanDefinition(func() *disposableImpl {
+			return &disposableImpl{}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		before := d.Value().Interface().(*disposableImpl)
+		if err := d.AfterSet(); err != nil {
+			t.Fatal(err)
+		}
+		after := d.Value().Interface().(*disposableImpl)
+		if err := d.Destroy(); err != nil {
+			t.Fatal(err)
+		}
+		if !before.destroyed {
+			t.Fatal("expect instance created before initialized destroyed")
+		}
+		if after.destroyed {
+			t.Fatal("expect instance created after initialized owned by caller")
+		}
+	})
+}
+
+type disposableImpl struct {
+	destroyed bool
+}
+
+func (o *disposableImpl) BeanDestroy() error {
+	o.destroyed = true
+	return nil
+}
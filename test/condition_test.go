@@ -0,0 +1,111 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"reflect"
+	"testing"
+)
+
+type conditionHolder struct {
+	A a `inject:""`
+}
+
+type conditionA struct{}
+
+func (a *conditionA) Get() string {
+	return "custom"
+}
+
+func TestCondition(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("property", func(t *testing.T) {
+		ctx := appcontext.NewDefaultApplicationContext()
+		if err := ctx.Init(conf); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBeanByName("matched", &aImpl{v: "matched"}, bean.OnProperty("userdata.value", "this is a test")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBeanByName("notMatched", &aImpl{v: "notMatched"}, bean.OnProperty("userdata.value", "xxx")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Close()
+		if _, ok := ctx.GetBean("matched"); !ok {
+			t.Fatal("expect matched bean")
+		}
+		if _, ok := ctx.GetBean("notMatched"); ok {
+			t.Fatal("expect notMatched bean removed")
+		}
+	})
+
+	t.Run("missing bean", func(t *testing.T) {
+		ctx := appcontext.NewDefaultApplicationContext()
+		if err := ctx.Init(conf); err != nil {
+			t.Fatal(err)
+		}
+		h := &conditionHolder{}
+		if err := ctx.RegisterBean(h); err != nil {
+			t.Fatal(err)
+		}
+		// 默认实现
+		if err := ctx.RegisterBean(func() *aImpl {
+			return &aImpl{v: "default"}
+		}, bean.OnMissingBean((*a)(nil))); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBean(&conditionA{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBeanByName("depend", &aImpl{v: "depend"},
+			bean.OnBean(reflect.TypeOf(&conditionA{})), bean.OnProperty("userdata.value", "xxx")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBeanByName("onName", &conditionHolder{}, bean.OnBean("depend")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBeanByName("onType", &conditionHolder{}, bean.OnBean(reflect.TypeOf(&conditionA{}))); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Close()
+		if h.A == nil || h.A.Get() != "custom" {
+			t.Fatal("expect custom bean injected")
+		}
+		if _, ok := ctx.GetBean("depend"); ok {
+			t.Fatal("expect depend bean removed")
+		}
+		if _, ok := ctx.GetBean("onName"); ok {
+			t.Fatal("expect onName bean removed")
+		}
+		if _, ok := ctx.GetBean("onType"); !ok {
+			t.Fatal("expect onType bean")
+		}
+	})
+}
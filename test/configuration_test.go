@@ -0,0 +1,160 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/processor"
+	"testing"
+)
+
+type cfgDataSource struct {
+	value string
+}
+
+type cfgRepo struct {
+	ds *cfgDataSource
+}
+
+type cfgService struct {
+	repo *cfgRepo
+}
+
+type cfgApp struct {
+	Repo    *cfgRepo    `inject:""`
+	Service *cfgService `inject:"service"`
+}
+
+type appConfiguration struct {
+	Value string `fig:"userdata.value"`
+
+	created int
+}
+
+func (c *appConfiguration) DataSourceBean() *cfgDataSource {
+	c.created++
+	return &cfgDataSource{value: c.Value}
+}
+
+func (c *appConfiguration) RepoBean(ds *cfgDataSource) (*cfgRepo, error) {
+	return &cfgRepo{ds: ds}, nil
+}
+
+// 不以Bean结尾的方法不会被注册
+func (c *appConfiguration) Helper() *cfgService {
+	return nil
+}
+
+type providerConfiguration struct{}
+
+func (c *providerConfiguration) BeanMethods() []bean.BeanMethod {
+	return []bean.BeanMethod{
+		{Method: "NewService", Name: "service"},
+	}
+}
+
+func (c *providerConfiguration) NewService(repo *cfgRepo) *cfgService {
+	return &cfgService{repo: repo}
+}
+
+func TestConfiguration(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	ctx.RegisterBean(processor.NewValueProcessor())
+	app := &cfgApp{}
+	// 使用方在配置类之前注册
+	ctx.RegisterBean(app)
+	cfg := &appConfiguration{}
+	if err := ctx.RegisterBean(cfg, bean.SetConfiguration()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(&providerConfiguration{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	if app.Repo == nil || app.Repo.ds == nil || app.Repo.ds.value != "this is a test" {
+		t.Fatal("expect repo created with configuration value")
+	}
+	if app.Service == nil || app.Service.repo != app.Repo {
+		t.Fatal("expect singleton service created by provider")
+	}
+	ds, err := appcontext.Get[*cfgDataSource](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds != app.Repo.ds || cfg.created != 1 {
+		t.Fatal("expect singleton data source")
+	}
+	if _, err := appcontext.Get[*cfgService](ctx); err == nil {
+		t.Fatal("Helper must not be registered")
+	}
+	g := ctx.DependencyGraph()
+	found := false
+	for _, e := range g.Edges {
+		if e.Function == "DataSourceBean" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expect dependency on configuration")
+	}
+}
+
+func TestConfigurationDynamic(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	ctx.RegisterBean(processor.NewValueProcessor())
+	ctx.RegisterBeanByName("other", &cfgDataSource{})
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	cfg := &appConfiguration{}
+	if err := ctx.RegisterBeanDynamic("", cfg, bean.SetConfiguration()); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := appcontext.Get[*cfgRepo](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.ds == nil || repo.ds.value != "this is a test" || cfg.created != 1 {
+		t.Fatal("expect repo created with configuration value")
+	}
+
+	if err := ctx.ReplaceBean("other", &providerConfiguration{}); err == nil {
+		t.Fatal("expect replace with configuration failed")
+	}
+}
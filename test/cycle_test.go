@@ -0,0 +1,135 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"strings"
+	"testing"
+)
+
+type circularA struct{}
+type circularB struct{}
+type circularC struct{}
+
+type circularObjA struct {
+	B *circularObjB `inject:""`
+}
+
+type circularObjB struct {
+	A *circularObjA `inject:""`
+}
+
+type circularConfiguration struct {
+	C *circularC `inject:"c"`
+}
+
+func (c *circularConfiguration) ABean() *circularA {
+	return &circularA{}
+}
+
+func TestCircularDependency(t *testing.T) {
+	t.Run("constructors", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBean(func(b *circularB) *circularA { return &circularA{} })
+		ctx.RegisterBean(bean.NewCustomBeanFactoryWithName(func(c *circularC) *circularB {
+			return &circularB{}
+		}, []string{"c"}, "", ""))
+		ctx.RegisterBeanByName("c", func(a *circularA) *circularC { return &circularC{} })
+		err := ctx.Start()
+		var cerr *appcontext.CircularDependencyError
+		if !errors.As(err, &cerr) {
+			t.Fatal("expect circular dependency error but get ", err)
+		}
+		if len(cerr.Cycles) != 1 || len(cerr.Cycles[0]) != 3 {
+			t.Fatal("expect 1 cycle with 3 hops, get ", cerr.Cycles)
+		}
+		if !strings.Contains(err.Error(), "circularA -> *github.com.xfali.neve-core.test.circularB -> c -> *github.com.xfali.neve-core.test.circularA") ||
+			!strings.Contains(err.Error(), "circularB [param:0] -> c") {
+			t.Fatal("expect readable cycle path but get ", err)
+		}
+		t.Log(err)
+	})
+
+	t.Run("configuration", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBean(&circularConfiguration{}, bean.SetConfiguration())
+		ctx.RegisterBeanByName("c", func(a *circularA) *circularC { return &circularC{} })
+		err := ctx.Start()
+		var cerr *appcontext.CircularDependencyError
+		if !errors.As(err, &cerr) {
+			t.Fatal("expect circular dependency error but get ", err)
+		}
+		if len(cerr.Cycles) != 1 || len(cerr.Cycles[0]) != 3 {
+			t.Fatal("expect 1 cycle with 3 hops, get ", cerr.Cycles)
+		}
+		if !strings.Contains(err.Error(), "[field:C] -> c") || !strings.Contains(err.Error(), "[ABean] ->") {
+			t.Fatal("expect field and bean method in cycle but get ", err)
+		}
+		t.Log(err)
+	})
+
+	t.Run("objects", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		a, b := &circularObjA{}, &circularObjB{}
+		ctx.RegisterBean(a)
+		ctx.RegisterBean(b)
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if a.B != b || b.A != a {
+			t.Fatal("expect objects injected each other")
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		a, b, o := &circularObjA{}, &circularObjB{}, &circularObjC{}
+		ctx.RegisterBean(a)
+		ctx.RegisterBean(b)
+		// 字段依赖与构造方法参数混合形成的循环
+		ctx.RegisterBean(o)
+		ctx.RegisterBeanByName("c", func(o *circularObjC) *circularC { return &circularC{} })
+		report, err := appcontext.Verify(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() || len(report.Cycles) != 0 || len(report.FieldCycles) != 2 {
+			t.Fatal("expect 2 field cycles, get ", report)
+		}
+		if !strings.Contains(report.String(), "[FIELD_CYCLE] *github.com.xfali.neve-core.test.circularObjC -> c -> *github.com.xfali.neve-core.test.circularObjC") {
+			t.Fatal("expect field cycle path, get ", report)
+		}
+		t.Log(report)
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if o.C == nil {
+			t.Fatal("expect created by constructor")
+		}
+	})
+}
+
+type circularObjC struct {
+	C *circularC `inject:"c"`
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"strings"
+	"testing"
+)
+
+var errDial = errors.New("dial failed")
+
+type factoryConn struct {
+	addr string
+}
+
+type factoryClient struct {
+	conn *factoryConn
+}
+
+type factoryHolder struct {
+	Conn *factoryConn `inject:"conn"`
+}
+
+type factoryClientHolder struct {
+	Client *factoryClient `inject:"client"`
+}
+
+func newFactoryContext(t *testing.T) appcontext.ApplicationContext {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestFactoryWithError(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBeanByName("conn", func() (*factoryConn, error) {
+			return &factoryConn{addr: "localhost"}, nil
+		}, bean.SetScope(bean.Singleton))
+		ctx.RegisterBeanByName("client", bean.NewCustomBeanFactoryWithName(func(conn *factoryConn) (*factoryClient, error) {
+			return &factoryClient{conn: conn}, nil
+		}, []string{"conn"}, "", ""))
+		h := &factoryClientHolder{}
+		ctx.RegisterBean(h)
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if h.Client == nil || h.Client.conn == nil || h.Client.conn.addr != "localhost" {
+			t.Fatal("expect client injected")
+		}
+	})
+
+	t.Run("startup failure", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBeanByName("conn", func() (*factoryConn, error) {
+			return nil, errDial
+		})
+		ctx.RegisterBean(&factoryHolder{})
+		err := ctx.Start()
+		if err == nil {
+			t.Fatal("expect start failed")
+		}
+		if !errors.Is(err, errDial) || !strings.Contains(err.Error(), "conn") {
+			t.Fatal("expect error naming bean conn but get ", err)
+		}
+		t.Log(err)
+	})
+
+	t.Run("nested factory failure", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBeanByName("conn", func() (*factoryConn, error) {
+			return nil, errDial
+		})
+		ctx.RegisterBeanByName("client", bean.NewCustomBeanFactoryWithName(func(conn *factoryConn) (*factoryClient, error) {
+			return &factoryClient{conn: conn}, nil
+		}, []string{"conn"}, "", ""))
+		ctx.RegisterBean(&factoryClientHolder{})
+		err := ctx.Start()
+		if !errors.Is(err, errDial) || !strings.Contains(err.Error(), "[conn]") {
+			t.Fatal("expect error naming bean conn but get ", err)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBeanByName("conn", func() (*factoryConn, error) {
+			return nil, errDial
+		})
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := ctx.GetBean("conn"); ok {
+			t.Fatal("expect get failed")
+		}
+		if _, err := appcontext.GetNamed[*factoryConn](ctx, "conn"); !errors.Is(err, errDial) {
+			t.Fatal("expect dial error but get ", err)
+		}
+	})
+}
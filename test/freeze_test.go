@@ -0,0 +1,149 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"sync"
+	"testing"
+)
+
+type freezeService struct {
+	set bool
+}
+
+func (s *freezeService) BeanAfterSet() error {
+	s.set = true
+	return nil
+}
+
+type freezeClient struct {
+	Service *freezeService `inject:"service"`
+}
+
+func TestFreeze(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	s := &freezeService{}
+	ctx.RegisterBeanByName("service", s)
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	if err := ctx.RegisterBean(&freezeClient{}); err != bean.ErrContainerFrozen {
+		t.Fatal("expect frozen error but get ", err)
+	}
+
+	c := &freezeClient{}
+	if err := ctx.RegisterBeanDynamic("", c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Service != s || !s.set {
+		t.Fatal("expect dynamic bean injected")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if v, ok := ctx.GetBean("service"); !ok || v != s {
+					t.Error("expect service")
+					return
+				}
+			}
+		}()
+	}
+	if err := ctx.RegisterBeanDynamic("other", &freezeService{}); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if _, ok := ctx.GetBean("other"); !ok {
+		t.Fatal("expect dynamic registered bean")
+	}
+}
+
+type freezeNamedClient struct {
+	Service *freezeService
+}
+
+func TestRegisterBeanDynamicInjectNames(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	s1, s2 := &freezeService{}, &freezeService{}
+	ctx.RegisterBeanByName("service1", s1)
+	ctx.RegisterBeanByName("service2", s2)
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	err = ctx.RegisterBeanDynamic("named", func(s *freezeService) *freezeNamedClient {
+		return &freezeNamedClient{Service: s}
+	}, bean.SetInjectNames("service2"), bean.SetScope(bean.Singleton))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, ok := ctx.GetBean("named")
+	if !ok || v.(*freezeNamedClient).Service != s2 {
+		t.Fatal("expect inject service2")
+	}
+
+	err = ctx.ReplaceBean("named", func(s *freezeService) *freezeNamedClient {
+		return &freezeNamedClient{Service: s}
+	}, bean.SetInjectNames("service1"), bean.SetScope(bean.Singleton))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, ok = ctx.GetBean("named")
+	if !ok || v.(*freezeNamedClient).Service != s1 {
+		t.Fatal("expect inject service1 after replaced")
+	}
+
+	// 条件不满足时不注册
+	err = ctx.RegisterBeanDynamic("missing", &freezeNamedClient{}, bean.OnMissingBean(&freezeService{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ctx.GetBean("missing"); ok {
+		t.Fatal("expect not registered")
+	}
+	// 条件不满足时保留原对象
+	err = ctx.ReplaceBean("service1", &freezeService{}, bean.OnProperty("neve.not.exists", "true"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := ctx.GetBean("service1"); !ok || v != s1 {
+		t.Fatal("expect service1 not replaced")
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"testing"
+)
+
+type genericService interface {
+	Name() string
+}
+
+type genericServiceA struct{}
+
+func (s *genericServiceA) Name() string { return "a" }
+
+type genericServiceB struct{}
+
+func (s *genericServiceB) Name() string { return "b" }
+
+type genericRepo interface {
+	Find() string
+}
+
+func TestGenericGet(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	a := &genericServiceA{}
+	b := &genericServiceB{}
+	ctx.RegisterBean(a)
+	ctx.RegisterBeanByName("serviceB", b, bean.SetAliases("b"))
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	t.Run("by type", func(t *testing.T) {
+		v, err := appcontext.Get[genericService](ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != a {
+			t.Fatal("expect a")
+		}
+		p, err := appcontext.Get[*genericServiceB](ctx)
+		if err == nil {
+			t.Fatal("expect error: named bean not auto wired, but get ", p)
+		}
+		t.Log(err)
+	})
+
+	t.Run("named", func(t *testing.T) {
+		v, err := appcontext.GetNamed[genericService](ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != b {
+			t.Fatal("expect b")
+		}
+		if _, err := appcontext.GetNamed[genericRepo](ctx, "serviceB"); err == nil {
+			t.Fatal("expect type error")
+		}
+		if _, err := appcontext.GetNamed[genericService](ctx, "notExist"); err == nil {
+			t.Fatal("expect not found error")
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		all := appcontext.GetAll[genericService](ctx)
+		if len(all) != 2 || all[0] != a || all[1] != b {
+			t.Fatal("expect [a, b] but get ", all)
+		}
+		if len(appcontext.GetAll[genericRepo](ctx)) != 0 {
+			t.Fatal("expect empty")
+		}
+	})
+
+	t.Run("missing and ambiguous", func(t *testing.T) {
+		if _, err := appcontext.Get[genericRepo](ctx); err == nil {
+			t.Fatal("expect missing error")
+		}
+		ctx2 := appcontext.NewDefaultApplicationContext()
+		ctx2.Init(conf)
+		ctx2.RegisterBean(a)
+		ctx2.RegisterBean(&genericServiceB{})
+		_, err := appcontext.Get[genericService](ctx2)
+		if err == nil {
+			t.Fatal("expect ambiguous error")
+		}
+		t.Log(err)
+	})
+
+	t.Run("GetBeanByType", func(t *testing.T) {
+		var v genericService
+		if !ctx.GetBeanByType(&v) || v != a {
+			t.Fatal("expect a")
+		}
+	})
+
+	t.Run("optional interfaces", func(t *testing.T) {
+		// 仅实现ApplicationContext的自定义ApplicationContext
+		custom := struct {
+			appcontext.ApplicationContext
+		}{ctx}
+		if _, err := appcontext.Get[genericService](custom); err == nil {
+			t.Fatal("expect not support resolve error")
+		}
+		if len(appcontext.GetAll[genericService](custom)) != 0 {
+			t.Fatal("expect empty")
+		}
+		if err := appcontext.ReplaceBean(custom, "b", &genericServiceB{}); err == nil {
+			t.Fatal("expect not support replace error")
+		}
+		if appcontext.DependencyGraphOf(custom) != nil || appcontext.ParentOf(custom) != nil {
+			t.Fatal("expect nil")
+		}
+		if appcontext.DependencyGraphOf(ctx) == nil {
+			t.Fatal("expect default ApplicationContext support dependency graph")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"encoding/json"
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"strings"
+	"testing"
+)
+
+type graphHolder struct {
+	A  a   `inject:""`
+	As []a `inject:""`
+	s  *graphService
+}
+
+func (h *graphHolder) RegisterFunction(registry appcontext.InjectFunctionRegistry) error {
+	return registry.RegisterInjectFunction(func(s *graphService) {
+		h.s = s
+	})
+}
+
+type graphService struct {
+	a a
+}
+
+func TestDependencyGraph(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBeanByName("holder", &graphHolder{}, bean.SetOrder(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(&aImpl{v: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(func(a a) *graphService {
+		return &graphService{a: a}
+	}, bean.SetScope(bean.Singleton)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	aName := "*github.com.xfali.neve-core.test.aImpl"
+	serviceName := "*github.com.xfali.neve-core.test.graphService"
+	g := ctx.DependencyGraph()
+	expect := []appcontext.BeanEdge{
+		{From: "holder", To: aName, Field: "A", Index: -1},
+		{From: "holder", To: aName, Field: "As", Index: -1},
+		{From: serviceName, To: aName, Index: 0},
+	}
+	for _, v := range expect {
+		found := false
+		for _, e := range g.Edges {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatal("expect edge: ", v)
+		}
+	}
+	funcEdge := false
+	for _, e := range g.Edges {
+		if e.From == "holder" && e.To == serviceName && e.Function != "" && e.Index == 0 {
+			funcEdge = true
+		}
+	}
+	if !funcEdge {
+		t.Fatal("expect function param edge from holder to service")
+	}
+	for _, n := range g.Nodes {
+		if n.Name == "holder" && n.Order != 1 {
+			t.Fatal("expect holder order 1 but get ", n.Order)
+		}
+	}
+
+	dot := g.DOT()
+	if !strings.HasPrefix(dot, "digraph") || !strings.Contains(dot, `"holder" -> "`+serviceName+`"`) {
+		t.Fatal("unexpected dot: ", dot)
+	}
+	t.Log(dot)
+
+	data, err := g.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := appcontext.DependencyGraph{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		t.Fatal(err)
+	}
+	if len(ret.Nodes) != len(g.Nodes) || len(ret.Edges) != len(g.Edges) {
+		t.Fatal("json not match")
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"testing"
+	"time"
+)
+
+type sharedBean struct {
+	destroyed bool
+}
+
+func (b *sharedBean) BeanDestroy() error {
+	b.destroyed = true
+	return nil
+}
+
+type childBean struct {
+	A      a           `inject:""`
+	Shared *sharedBean `inject:""`
+}
+
+type childEventListener struct {
+	events chan *customerEvent
+}
+
+func (l *childEventListener) OnApplicationEvent(e appcontext.ApplicationEvent) {
+	if v, ok := e.(*customerEvent); ok {
+		l.events <- v
+	}
+	if _, ok := e.(*appcontext.ContextStartedEvent); ok {
+		l.events <- nil
+	}
+}
+
+func TestHierarchicalContext(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := appcontext.NewDefaultApplicationContext()
+	if err := parent.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	shared := &sharedBean{}
+	if err := parent.RegisterBean(shared); err != nil {
+		t.Fatal(err)
+	}
+	if err := parent.RegisterBean(&aImpl{v: "parent"}); err != nil {
+		t.Fatal(err)
+	}
+	l := &childEventListener{events: make(chan *customerEvent, 8)}
+	parent.AddListeners(l)
+	if err := parent.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer parent.Close()
+	// parent started event
+	<-l.events
+
+	child := appcontext.NewChildApplicationContext(parent, appcontext.OptPropagateEvent())
+	if err := child.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	c := &childBean{}
+	if err := child.RegisterBean(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := child.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if child.GetParent() != parent {
+		t.Fatal("expect parent")
+	}
+	if c.A == nil || c.A.Get() != "parent" || c.Shared != shared {
+		t.Fatal("expect inject parent beans")
+	}
+	if _, ok := child.GetBean("*github.com.xfali.neve-core.test.sharedBean"); !ok {
+		t.Fatal("expect get parent bean from child")
+	}
+	if _, ok := parent.GetBean("*github.com.xfali.neve-core.test.childBean"); ok {
+		t.Fatal("parent must not see child bean")
+	}
+
+	if err := child.PublishEvent(newCustomerEvent("from child")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-l.events:
+		if e == nil || e.payload != "from child" {
+			t.Fatal("expect child event but get ", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect event propagated to parent")
+	}
+
+	child.Close()
+	if shared.destroyed {
+		t.Fatal("child close must not destroy parent bean")
+	}
+
+	// OptSetContainer不影响父ApplicationContext的配置
+	other := appcontext.NewChildApplicationContext(parent, appcontext.OptSetContainer(bean.NewContainer()))
+	if err := other.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if _, ok := other.GetBean("*github.com.xfali.neve-core.test.sharedBean"); !ok {
+		t.Fatal("expect get parent bean from child with container option")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/xfali/neve-core/bean"
 	"github.com/xfali/neve-core/injector"
 	"io"
+	"reflect"
 	"testing"
 )
 
@@ -607,3 +608,52 @@ func TestInjectTagChange(t *testing.T) {
 		}
 	})
 }
+
+func TestWrapBean(t *testing.T) {
+	c := bean.NewContainer()
+	c.Register(&aImpl{})
+	i := injector.New()
+	lm := injector.NewListenerManager()
+
+	t.Run("without creation", func(t *testing.T) {
+		f, err := injector.WrapBean(func(v *aImpl) *bImpl {
+			return &bImpl{i: v.Get() + 10}
+		}, c, i, lm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fn, ok := f.(func() *bImpl)
+		if !ok {
+			t.Fatal("expect func() *bImpl but get ", reflect.TypeOf(f))
+		}
+		if fn().Get() != 11 {
+			t.Fatal("expect 11")
+		}
+
+		f, err = injector.WrapBeanFactoryFunc(func(v *aImpl) (*bImpl, error) {
+			return &bImpl{i: v.Get()}, nil
+		}, c, i, lm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := f.(func() (*bImpl, error)); !ok {
+			t.Fatal("expect func() (*bImpl, error) but get ", reflect.TypeOf(f))
+		}
+	})
+
+	t.Run("with creation", func(t *testing.T) {
+		f, err := injector.WrapBeanWithCreation(func(v *aImpl) *bImpl {
+			return &bImpl{i: v.Get() + 10}
+		}, c, i, lm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fn, ok := f.(func(*bean.Creation) *bImpl)
+		if !ok {
+			t.Fatal("expect func(*bean.Creation) *bImpl but get ", reflect.TypeOf(f))
+		}
+		if fn(nil).Get() != 11 {
+			t.Fatal("expect 11")
+		}
+	})
+}
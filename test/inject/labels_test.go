@@ -0,0 +1,69 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inject
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"reflect"
+	"testing"
+)
+
+type labelsDest struct {
+	All      []a          `inject:""`
+	Handlers []a          `inject:",labels=kind=handler"`
+	Web      map[string]a `inject:",labels=kind=handler,labels=tier=web"`
+	Tier     []a          `inject:",labels=tier"`
+}
+
+func TestInjectLabels(t *testing.T) {
+	c := bean.NewContainer()
+	c.Register(&aImpl{}, bean.SetLabels("kind=handler", "tier=web"), bean.SetAttribute("route", "/users"))
+	c.RegisterByName("b", &bImpl{i: 3}, bean.SetLabels("kind=handler", "tier=rpc"))
+	c.RegisterByName("c", &bImpl{i: 4}, bean.SetLabels("kind=job"))
+	i := injector.New()
+
+	d := labelsDest{Web: map[string]a{}}
+	err := i.Inject(c, &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.All) != 3 {
+		t.Fatal("expect 3 but get ", len(d.All))
+	}
+	if len(d.Handlers) != 2 || d.Handlers[0].Get() != 1 || d.Handlers[1].Get() != 3 {
+		t.Fatal("expect 2 handlers but get ", len(d.Handlers))
+	}
+	if len(d.Web) != 1 {
+		t.Fatal("expect 1 web handler but get ", len(d.Web))
+	}
+	if len(d.Tier) != 2 {
+		t.Fatal("expect 2 beans with tier label but get ", len(d.Tier))
+	}
+
+	def, _ := c.GetDefinition("b")
+	if bean.LabelsOf(def)["tier"] != "rpc" {
+		t.Fatal("expect label tier=rpc but get ", bean.LabelsOf(def))
+	}
+	if _, ok := bean.AttributeOf(def, "route"); ok {
+		t.Fatal("expect no route attribute")
+	}
+	def = bean.FindByType(c, reflect.TypeOf(&aImpl{}))[0]
+	if route, _ := bean.AttributeOf(def, "route"); route != "/users" {
+		t.Fatal("expect route /users but get ", route)
+	}
+}
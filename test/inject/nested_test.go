@@ -0,0 +1,106 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inject
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"strings"
+	"testing"
+)
+
+type nestedRepo struct{}
+
+type nestedMissing struct{}
+
+type nestedDeps struct {
+	Repo    *nestedRepo    `inject:""`
+	Missing *nestedMissing `inject:",capture"`
+}
+
+type nestedBase struct {
+	BaseRepo *nestedRepo `inject:""`
+}
+
+type NestedPtrBase struct {
+	PtrRepo *nestedRepo `inject:""`
+}
+
+type nestedService struct {
+	nestedBase
+	*NestedPtrBase
+	Deps nestedDeps `inject:""`
+}
+
+type NestedNode struct {
+	*NestedNode
+	Repo *nestedRepo `inject:""`
+}
+
+type captureListener struct {
+	errs []error
+}
+
+func (l *captureListener) OnInjectFailed(err error) {
+	l.errs = append(l.errs, err)
+}
+
+func TestInjectNested(t *testing.T) {
+	c := bean.NewContainer()
+	repo := &nestedRepo{}
+	c.Register(repo)
+
+	t.Run("embedded and nested", func(t *testing.T) {
+		l := &captureListener{}
+		i := injector.New(injector.OptSetListener("capture", l))
+		s := nestedService{}
+		if err := i.Inject(c, &s); err != nil {
+			t.Fatal(err)
+		}
+		if s.BaseRepo != repo || s.NestedPtrBase == nil || s.PtrRepo != repo || s.Deps.Repo != repo {
+			t.Fatal("expect nested fields injected but get ", s)
+		}
+		if len(l.errs) != 1 || !strings.Contains(l.errs[0].Error(), "nestedService: Deps.Missing]") {
+			t.Fatal("expect error with field path but get ", l.errs)
+		}
+	})
+
+	t.Run("registered struct", func(t *testing.T) {
+		c := bean.NewContainer()
+		other := &nestedRepo{}
+		c.Register(repo)
+		c.Register(nestedDeps{Repo: other})
+		i := injector.New(injector.OptSetListener("capture", &captureListener{}))
+		s := nestedService{}
+		if err := i.Inject(c, &s); err != nil {
+			t.Fatal(err)
+		}
+		if s.Deps.Repo != other {
+			t.Fatal("expect registered struct injected but get ", s.Deps)
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		i := injector.New()
+		n := NestedNode{}
+		err := i.Inject(c, &n)
+		if err == nil || !strings.Contains(err.Error(), "recursive struct") {
+			t.Fatal("expect recursive error but get ", err)
+		}
+		t.Log(err)
+	})
+}
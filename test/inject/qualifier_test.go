@@ -0,0 +1,137 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inject
+
+import (
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type qualifierDest struct {
+	A  a   `inject:""`
+	F  a   `inject:",qualifier=fast"`
+	FS []a `inject:",qualifier=fast"`
+}
+
+func TestInjectPrimary(t *testing.T) {
+	t.Run("ambiguous", func(t *testing.T) {
+		c := bean.NewContainer()
+		c.Register(&aImpl{})
+		c.Register(&bImpl{})
+		i := injector.New()
+
+		var v a
+		err := i.InjectValue(c, "", reflect.ValueOf(&v).Elem())
+		if err == nil {
+			t.Fatal("expect ambiguous error")
+		}
+		if !strings.Contains(err.Error(), "aImpl") || !strings.Contains(err.Error(), "bImpl") {
+			t.Fatal("error must contain candidates, but get: ", err)
+		}
+		t.Log(err)
+	})
+
+	t.Run("primary", func(t *testing.T) {
+		c := bean.NewContainer()
+		c.Register(&aImpl{})
+		c.Register(&bImpl{}, bean.SetPrimary())
+		i := injector.New()
+
+		d := qualifierDest{}
+		err := i.InjectValue(c, "", reflect.ValueOf(&d.A).Elem())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.A.Get() != 2 {
+			t.Fatal("expect primary bImpl but get ", d.A.Get())
+		}
+	})
+
+	t.Run("named primary", func(t *testing.T) {
+		c := bean.NewContainer()
+		c.RegisterByName("fast", &bImpl{i: 3}, bean.SetPrimary())
+		i := injector.New()
+
+		var v a
+		if err := i.InjectValue(c, "", reflect.ValueOf(&v).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if v.Get() != 3 {
+			t.Fatal("expect named primary bImpl but get ", v.Get())
+		}
+
+		c = bean.NewContainer()
+		c.Register(&aImpl{})
+		c.RegisterByName("fast", &bImpl{i: 3}, bean.SetPrimary())
+		d, err := injector.Resolve(c, "", reflect.TypeOf(&v).Elem())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Value().Interface().(a).Get() != 3 {
+			t.Fatal("expect named primary bImpl but get ", d.Name())
+		}
+	})
+
+	t.Run("primary before default name", func(t *testing.T) {
+		var v a
+		vt := reflect.TypeOf(&v).Elem()
+		c := bean.NewContainer()
+		c.RegisterByName(reflection.GetTypeName(vt), &aImpl{})
+		c.RegisterByName("fast", &bImpl{i: 3}, bean.SetPrimary())
+		i := injector.New()
+
+		if err := i.InjectValue(c, "", reflect.ValueOf(&v).Elem()); err != nil {
+			t.Fatal(err)
+		}
+		if v.Get() != 3 {
+			t.Fatal("expect named primary bImpl but get ", v.Get())
+		}
+		d, err := injector.Resolve(c, "", vt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Value().Interface().(a).Get() != 3 {
+			t.Fatal("expect named primary bImpl but get ", d.Name())
+		}
+	})
+}
+
+func TestInjectQualifier(t *testing.T) {
+	c := bean.NewContainer()
+	c.Register(&aImpl{})
+	c.RegisterByName("fast", &bImpl{i: 3}, bean.SetQualifier("fast"))
+	i := injector.New()
+
+	d := qualifierDest{}
+	err := i.Inject(c, &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.A.Get() != 1 {
+		t.Fatal("expect aImpl but get ", d.A.Get())
+	}
+	if d.F.Get() != 3 {
+		t.Fatal("expect qualifier fast but get ", d.F.Get())
+	}
+	if len(d.FS) != 1 || d.FS[0].Get() != 3 {
+		t.Fatal("expect 1 qualifier fast but get ", len(d.FS))
+	}
+}
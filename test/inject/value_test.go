@@ -0,0 +1,136 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inject
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"testing"
+	"time"
+)
+
+type valueConfig struct {
+	Host string
+	Port int
+}
+
+type valueDest struct {
+	Timeout time.Duration `inject:"timeout"`
+	Name    string        `inject:"name"`
+	Retries int           `inject:"retries"`
+	Config  valueConfig   `inject:""`
+	Region  string        `inject:",qualifier=region"`
+	// 基础类型不会自动匹配其他同类型的对象
+	Missing string `inject:",omiterror"`
+}
+
+func TestInjectValue(t *testing.T) {
+	c := bean.NewContainer()
+	if err := c.RegisterByName("timeout", 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	c.RegisterByName("name", "svc")
+	c.RegisterByName("retries", 3)
+	c.RegisterByName("region", "cn-east", bean.SetQualifier("region"))
+	if err := c.Register(valueConfig{Host: "localhost", Port: 8080}); err != nil {
+		t.Fatal(err)
+	}
+	i := injector.New()
+
+	d := valueDest{}
+	if err := i.Inject(c, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Timeout != 5*time.Second || d.Name != "svc" || d.Retries != 3 || d.Region != "cn-east" {
+		t.Fatal("expect values injected but get ", d)
+	}
+	if d.Config.Host != "localhost" || d.Config.Port != 8080 {
+		t.Fatal("expect config injected but get ", d.Config)
+	}
+	if d.Missing != "" {
+		t.Fatal("expect missing not injected but get ", d.Missing)
+	}
+
+	// 注入的是值的拷贝
+	d.Config.Host = "changed"
+	d2 := valueDest{}
+	if err := i.Inject(c, &d2); err != nil {
+		t.Fatal(err)
+	}
+	if d2.Config.Host != "localhost" {
+		t.Fatal("expect container value not changed but get ", d2.Config.Host)
+	}
+	if v, ok := c.Get("timeout"); !ok || v.(time.Duration) != 5*time.Second {
+		t.Fatal("expect get timeout but get ", v)
+	}
+	var conf valueConfig
+	if !c.GetByType(&conf) || conf.Port != 8080 {
+		t.Fatal("expect get config by type but get ", conf)
+	}
+}
+
+type valueSliceDest struct {
+	Names []string          `inject:""`
+	Ts    []time.Duration   `inject:""`
+	Ports map[string]uint16 `inject:",omiterror"`
+}
+
+func TestInjectValueSlice(t *testing.T) {
+	c := bean.NewContainer()
+	c.RegisterByName("port", 65)
+	c.RegisterByName("timeout", 5*time.Second)
+	c.RegisterByName("host", "localhost")
+	c.RegisterByName("ratio", 2.5)
+	i := injector.New()
+
+	d := valueSliceDest{}
+	if err := i.Inject(c, &d); err != nil {
+		t.Fatal(err)
+	}
+	// 值类型的对象仅在类型可赋值时才会被收集，不会转换为其他类型
+	if len(d.Names) != 1 || d.Names[0] != "localhost" {
+		t.Fatal("expect names [localhost] but get ", d.Names)
+	}
+	if len(d.Ts) != 1 || d.Ts[0] != 5*time.Second {
+		t.Fatal("expect ts [5s] but get ", d.Ts)
+	}
+	if len(d.Ports) != 0 {
+		t.Fatal("expect no ports but get ", d.Ports)
+	}
+}
+
+type propertyDest struct {
+	Port    int           `inject:"${server.port:8080}"`
+	Host    string        `inject:"${server.host}"`
+	Timeout time.Duration `inject:"${server.timeout:3s}"`
+}
+
+func TestInjectProperty(t *testing.T) {
+	props := fig.NewSettableProperties()
+	if err := props.Set("server", map[string]interface{}{"host": "localhost"}); err != nil {
+		t.Fatal(err)
+	}
+	i := injector.New(injector.OptSetProperties(props))
+	d := propertyDest{}
+	if err := i.Inject(bean.NewContainer(), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Port != 8080 || d.Host != "localhost" || d.Timeout != 3*time.Second {
+		t.Fatal("expect values from properties but get ", d)
+	}
+}
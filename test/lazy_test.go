@@ -0,0 +1,218 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/processor"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type lazyBean struct {
+	A         a      `inject:""`
+	V         string `fig:"userdata.value"`
+	set       int
+	destroyed int
+}
+
+func (b *lazyBean) BeanAfterSet() error {
+	b.set++
+	return nil
+}
+
+func (b *lazyBean) BeanDestroy() error {
+	b.destroyed++
+	return nil
+}
+
+type slowLazyBean struct {
+	A   a `inject:""`
+	set int32
+}
+
+func (b *slowLazyBean) BeanAfterSet() error {
+	time.Sleep(20 * time.Millisecond)
+	atomic.AddInt32(&b.set, 1)
+	return nil
+}
+
+type lazyCycleA struct {
+	B *lazyCycleB `inject:"b"`
+}
+
+type lazyCycleB struct {
+	A *lazyCycleA `inject:"a"`
+}
+
+func newLazyContext(t *testing.T, conf fig.Properties) appcontext.ApplicationContext {
+	ctx := appcontext.NewDefaultApplicationContext()
+	err := ctx.Init(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ctx.RegisterBean(processor.NewValueProcessor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestLazy(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("unused", func(t *testing.T) {
+		ctx := newLazyContext(t, conf)
+		o := &lazyBean{}
+		constructed := false
+		if err := ctx.RegisterBean(o, bean.SetLazy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBean(func() *aImpl {
+			constructed = true
+			return &aImpl{v: "lazy"}
+		}, bean.SetLazy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Close()
+		if o.A != nil || o.V != "" || o.set != 0 || o.destroyed != 0 {
+			t.Fatal("lazy bean must not be initialized")
+		}
+		if constructed {
+			t.Fatal("lazy bean must not be constructed")
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		ctx := newLazyContext(t, conf)
+		o := &lazyBean{}
+		if err := ctx.RegisterBeanByName("lazy", o, bean.SetLazy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBean(&aImpl{v: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if o.A != nil || o.set != 0 {
+			t.Fatal("lazy bean must not be initialized before get")
+		}
+		v, ok := ctx.GetBean("lazy")
+		if !ok || v != o {
+			t.Fatal("get lazy bean failed")
+		}
+		if o.A == nil || o.A.Get() != "a" || o.V != "this is a test" || o.set != 1 {
+			t.Fatal("lazy bean not initialized")
+		}
+		ctx.GetBean("lazy")
+		ctx.Close()
+		if o.set != 1 || o.destroyed != 1 {
+			t.Fatal("expect init and destroy once, but get ", o.set, o.destroyed)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		ctx := newLazyContext(t, conf)
+		o := &slowLazyBean{}
+		if err := ctx.RegisterBeanByName("lazy", o, bean.SetLazy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBean(&aImpl{v: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Close()
+
+		// 第一次同时获取时，所有调用方都应得到初始化完成的对象
+		wg := sync.WaitGroup{}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, ok := ctx.GetBean("lazy")
+				if !ok || v != o {
+					t.Error("get lazy bean failed")
+					return
+				}
+				if o.A == nil || atomic.LoadInt32(&o.set) != 1 {
+					t.Error("expect lazy bean initialized before returned")
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		ctx := newLazyContext(t, conf)
+		a := &lazyCycleA{}
+		b := &lazyCycleB{}
+		if err := ctx.RegisterBeanByName("a", a, bean.SetLazy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBeanByName("b", b, bean.SetLazy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Close()
+		if v, ok := ctx.GetBean("a"); !ok || v != a {
+			t.Fatal("get lazy bean failed")
+		}
+		if a.B != b || b.A != a {
+			t.Fatal("expect lazy beans injected with each other")
+		}
+	})
+
+	t.Run("global", func(t *testing.T) {
+		prop := fig.NewSettableProperties()
+		prop.Set("neve", map[string]interface{}{
+			"application": map[string]interface{}{
+				"lazyInit":   "true",
+				"bannerMode": "off",
+			},
+		})
+		ctx := newLazyContext(t, prop)
+		o := &lazyBean{}
+		if err := ctx.RegisterBean(o); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.RegisterBean(&aImpl{v: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Close()
+		if o.A != nil || o.set != 0 {
+			t.Fatal("lazy bean must not be initialized")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"reflect"
+	"testing"
+)
+
+type lifecycleRecorder struct {
+	set     []string
+	destroy []string
+}
+
+type lifecycleDB struct {
+	r *lifecycleRecorder
+}
+
+func (b *lifecycleDB) BeanAfterSet() error {
+	b.r.set = append(b.r.set, "db")
+	return nil
+}
+
+func (b *lifecycleDB) BeanDestroy() error {
+	b.r.destroy = append(b.r.destroy, "db")
+	return nil
+}
+
+type lifecycleRepo struct {
+	DB *lifecycleDB `inject:""`
+	r  *lifecycleRecorder
+}
+
+func (b *lifecycleRepo) BeanAfterSet() error {
+	b.r.set = append(b.r.set, "repo")
+	return nil
+}
+
+func (b *lifecycleRepo) BeanDestroy() error {
+	b.r.destroy = append(b.r.destroy, "repo")
+	return nil
+}
+
+type lifecycleService struct {
+	Repo *lifecycleRepo `inject:""`
+	r    *lifecycleRecorder
+	name string
+}
+
+func (b *lifecycleService) BeanAfterSet() error {
+	b.r.set = append(b.r.set, b.name)
+	return nil
+}
+
+func (b *lifecycleService) BeanDestroy() error {
+	b.r.destroy = append(b.r.destroy, b.name)
+	return nil
+}
+
+type cycleA struct {
+	B *cycleB `inject:""`
+}
+
+type cycleB struct {
+	A *cycleA `inject:""`
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &lifecycleRecorder{}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	// 注册顺序与依赖顺序相反
+	if err := ctx.RegisterBeanByName("s2", &lifecycleService{r: r, name: "s2"}, bean.SetOrder(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBeanByName("s1", &lifecycleService{r: r, name: "s1"}, bean.SetOrder(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(&lifecycleRepo{r: r}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(&lifecycleDB{r: r}, bean.SetOrder(3)); err != nil {
+		t.Fatal(err)
+	}
+	// 循环依赖不影响启动
+	if err := ctx.RegisterBean(&cycleA{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(&cycleB{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Close()
+
+	expectSet := []string{"db", "repo", "s1", "s2"}
+	if !reflect.DeepEqual(r.set, expectSet) {
+		t.Fatal("expect init order ", expectSet, " but get ", r.set)
+	}
+	expectDestroy := []string{"s2", "s1", "repo", "db"}
+	if !reflect.DeepEqual(r.destroy, expectDestroy) {
+		t.Fatal("expect destroy order ", expectDestroy, " but get ", r.destroy)
+	}
+}
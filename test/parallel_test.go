@@ -0,0 +1,214 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"fmt"
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type slowBean struct {
+	set int32
+}
+
+func (b *slowBean) BeanAfterSet() error {
+	time.Sleep(100 * time.Millisecond)
+	atomic.StoreInt32(&b.set, 1)
+	return nil
+}
+
+type slowDependent struct {
+	Dep *slowBean `inject:"slow0"`
+
+	depSet int32
+}
+
+func (b *slowDependent) BeanAfterSet() error {
+	b.depSet = atomic.LoadInt32(&b.Dep.set)
+	return nil
+}
+
+func TestParallelInit(t *testing.T) {
+	prop := fig.NewSettableProperties()
+	prop.Set("neve", map[string]interface{}{
+		"application": map[string]interface{}{
+			"bannerMode": "off",
+		},
+		"inject": map[string]interface{}{
+			"workers": "4",
+		},
+	})
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(prop); err != nil {
+		t.Fatal(err)
+	}
+	var beans []*slowBean
+	for i := 0; i < 4; i++ {
+		b := &slowBean{}
+		beans = append(beans, b)
+		ctx.RegisterBeanByName(fmt.Sprintf("slow%d", i), b)
+	}
+	dependent := &slowDependent{}
+	ctx.RegisterBean(dependent)
+	// order更大的对象在order更小的对象初始化完成之后初始化
+	last := &slowBean{}
+	ctx.RegisterBeanByName("last", last, bean.SetOrder(1))
+
+	now := time.Now()
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	elapsed := time.Since(now)
+	// 4个并行（100ms）+ order为1的对象（100ms）
+	if elapsed >= 400*time.Millisecond {
+		t.Fatal("expect parallel init but cost ", elapsed)
+	}
+	for _, b := range beans {
+		if b.set != 1 {
+			t.Fatal("expect initialized")
+		}
+	}
+	if dependent.depSet != 1 {
+		t.Fatal("dependency must be initialized before dependent")
+	}
+	if last.set != 1 {
+		t.Fatal("expect last initialized")
+	}
+	t.Log("cost ", elapsed)
+}
+
+type slowProduct struct{}
+
+type slowHolder struct {
+	P *slowProduct `inject:""`
+}
+
+func TestParallelInject(t *testing.T) {
+	prop := fig.NewSettableProperties()
+	prop.Set("neve", map[string]interface{}{
+		"application": map[string]interface{}{
+			"bannerMode": "off",
+		},
+		"inject": map[string]interface{}{
+			"workers": "4",
+		},
+	})
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(prop); err != nil {
+		t.Fatal(err)
+	}
+	var created int32
+	ctx.RegisterBean(func() *slowProduct {
+		time.Sleep(100 * time.Millisecond)
+		atomic.AddInt32(&created, 1)
+		return &slowProduct{}
+	})
+	var holders []*slowHolder
+	for i := 0; i < 4; i++ {
+		h := &slowHolder{}
+		holders = append(holders, h)
+		ctx.RegisterBeanByName(fmt.Sprintf("holder%d", i), h)
+	}
+
+	now := time.Now()
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	elapsed := time.Since(now)
+	// 4个依赖方并行注入，构造方法（100ms）并行执行
+	if elapsed >= 400*time.Millisecond {
+		t.Fatal("expect parallel inject but cost ", elapsed)
+	}
+	for _, h := range holders {
+		if h.P == nil {
+			t.Fatal("expect injected")
+		}
+	}
+	if created != 4 {
+		t.Fatal("expect prototype created for each holder but get ", created)
+	}
+	t.Log("cost ", elapsed)
+}
+
+type lazyCrossA struct {
+	// 注入耗时的原型，保证两个任务同时持有各自延迟初始化的对象
+	P *slowProduct `inject:""`
+	B *lazyCrossB  `inject:"crossB"`
+}
+
+type lazyCrossB struct {
+	P *slowProduct `inject:""`
+	A *lazyCrossA  `inject:"crossA"`
+}
+
+type lazyCrossRootA struct {
+	A *lazyCrossA `inject:"crossA"`
+}
+
+type lazyCrossRootB struct {
+	B *lazyCrossB `inject:"crossB"`
+}
+
+func TestParallelInjectLazyCycle(t *testing.T) {
+	prop := fig.NewSettableProperties()
+	prop.Set("neve", map[string]interface{}{
+		"application": map[string]interface{}{
+			"bannerMode": "off",
+		},
+		"inject": map[string]interface{}{
+			"workers": "4",
+		},
+	})
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(prop); err != nil {
+		t.Fatal(err)
+	}
+	ctx.RegisterBean(func() *slowProduct {
+		time.Sleep(100 * time.Millisecond)
+		return &slowProduct{}
+	})
+	a, b := &lazyCrossA{}, &lazyCrossB{}
+	ctx.RegisterBeanByName("crossA", a, bean.SetLazy())
+	ctx.RegisterBeanByName("crossB", b, bean.SetLazy())
+	rootA, rootB := &lazyCrossRootA{}, &lazyCrossRootB{}
+	ctx.RegisterBean(rootA)
+	ctx.RegisterBean(rootB)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ctx.Start()
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock: start timeout")
+	}
+	defer ctx.Close()
+	if rootA.A != a || rootB.B != b || a.B != b || b.A != a {
+		t.Fatal("expect lazy cycle injected")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"testing"
+	"time"
+)
+
+type placeholderRepo struct{}
+
+type placeholderServer struct {
+	value   string
+	sleep   int
+	port    int
+	timeout time.Duration
+	repo    *placeholderRepo
+}
+
+func newPlaceholderServer(value string, sleep int, port int, timeout time.Duration, repo *placeholderRepo) *placeholderServer {
+	return &placeholderServer{
+		value:   value,
+		sleep:   sleep,
+		port:    port,
+		timeout: timeout,
+		repo:    repo,
+	}
+}
+
+type placeholderClient struct {
+	value string
+}
+
+func TestPlaceholder(t *testing.T) {
+	t.Run("register option", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBean(&placeholderRepo{})
+		err := ctx.RegisterBean(newPlaceholderServer, bean.SetScope(bean.Singleton),
+			bean.SetInjectNames("${userdata.value}", "${neve.application.quit.sleepSec}", "${server.port:8080}", "${server.timeout:3s}", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		s, err := appcontext.Get[*placeholderServer](ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.value != "this is a test" || s.sleep != 5 || s.port != 8080 || s.timeout != 3*time.Second || s.repo == nil {
+			t.Fatal("expect values from properties but get ", s)
+		}
+	})
+
+	t.Run("custom bean factory", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBean(bean.NewCustomBeanFactoryWithName(func(v string) *placeholderClient {
+			return &placeholderClient{value: v}
+		}, []string{"${userdata.value}"}, "", ""))
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		c, err := appcontext.Get[*placeholderClient](ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.value != "this is a test" {
+			t.Fatal("expect value from properties but get ", c.value)
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBean(func(port int) *placeholderClient {
+			return &placeholderClient{}
+		}, bean.SetInjectNames("${server.port}"))
+		report, err := appcontext.Verify(ctx)
+		if err == nil {
+			t.Fatal("expect verify failed")
+		}
+		if len(report.Unresolved) != 1 {
+			t.Fatal("expect missing property unresolved, get ", report)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		err := ctx.RegisterBean(&placeholderRepo{}, bean.SetInjectNames("${server.port}"))
+		if err == nil {
+			t.Fatal("expect error when set inject names to object")
+		}
+	})
+}
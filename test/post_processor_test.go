@@ -0,0 +1,161 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type postGreeter interface {
+	Greet() string
+}
+
+type postGreeterImpl struct {
+	set bool
+}
+
+func (g *postGreeterImpl) Greet() string {
+	return "hello"
+}
+
+func (g *postGreeterImpl) BeanAfterSet() error {
+	g.set = true
+	return nil
+}
+
+// 统计调用次数的装饰器
+type countingGreeter struct {
+	postGreeter
+	count int
+}
+
+func (g *countingGreeter) Greet() string {
+	g.count++
+	return g.postGreeter.Greet()
+}
+
+type postGreeterHolder struct {
+	Greeter  postGreeter   `inject:""`
+	Named    postGreeter   `inject:"greeter"`
+	Greeters []postGreeter `inject:""`
+}
+
+type plainBean struct {
+	set bool
+}
+
+func (b *plainBean) BeanAfterSet() error {
+	b.set = true
+	return nil
+}
+
+type decoratingProcessor struct {
+	events []string
+	lock   sync.Mutex
+}
+
+func (p *decoratingProcessor) record(e string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.events = append(p.events, e)
+}
+
+func (p *decoratingProcessor) BeforeInit(name string, o interface{}) (interface{}, error) {
+	if g, ok := o.(*postGreeterImpl); ok {
+		p.record("before " + name[strings.LastIndex(name, ".")+1:] + " " + boolString(g.set))
+	}
+	return o, nil
+}
+
+func (p *decoratingProcessor) AfterInit(name string, o interface{}) (interface{}, error) {
+	switch v := o.(type) {
+	case *postGreeterImpl:
+		p.record("after " + name[strings.LastIndex(name, ".")+1:] + " " + boolString(v.set))
+		return &countingGreeter{postGreeter: v}, nil
+	case *plainBean:
+		// 类型无法赋值给对象定义的类型，不替换
+		return &countingGreeter{}, nil
+	}
+	return o, nil
+}
+
+func boolString(b bool) string {
+	if b {
+		return "set"
+	}
+	return "unset"
+}
+
+func TestBeanPostProcessor(t *testing.T) {
+	ctx := newFactoryContext(t)
+	p := &decoratingProcessor{}
+	impl := &postGreeterImpl{}
+	holder := &postGreeterHolder{}
+	plain := &plainBean{}
+	if err := ctx.RegisterBean(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(holder); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(func() postGreeter {
+		return impl
+	}, bean.SetScope(bean.Singleton), bean.SetAliases("greeter")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	if strings.Join(p.events, ",") != "before postGreeter unset,after postGreeter set" {
+		t.Fatal("expect post processor called around BeanAfterSet, got ", p.events)
+	}
+	if !impl.set {
+		t.Fatal("expect original greeter initialized")
+	}
+	for _, g := range []postGreeter{holder.Greeter, holder.Named} {
+		c, ok := g.(*countingGreeter)
+		if !ok || c.postGreeter != impl {
+			t.Fatalf("expect decorated greeter, got %T", g)
+		}
+	}
+	if len(holder.Greeters) != 1 {
+		t.Fatal("expect 1 greeter in slice")
+	}
+	if _, ok := holder.Greeters[0].(*countingGreeter); !ok {
+		t.Fatalf("expect decorated greeter in slice, got %T", holder.Greeters[0])
+	}
+	holder.Greeter.Greet()
+	if v, ok := ctx.GetBean("greeter"); !ok || v.(*countingGreeter).count != 1 {
+		t.Fatal("expect get decorated greeter")
+	}
+
+	if !plain.set {
+		t.Fatal("expect plain bean initialized")
+	}
+	if v, err := appcontext.Get[*plainBean](ctx); err != nil || v != plain {
+		t.Fatal("expect plain bean not replaced by unassignable object")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"os"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	configPath := "assets/application-test.yaml"
+	conf, err := fig.LoadYamlFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv(appcontext.EnvNameProfilesActive, "local, dev")
+	defer os.Unsetenv(appcontext.EnvNameProfilesActive)
+
+	t.Run("config", func(t *testing.T) {
+		prop, err := neve.LoadProfileProperties(configPath, conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := prop.Get("userdata.value", ""); v != "this is a dev test" {
+			t.Fatal("expect dev value but get: ", v)
+		}
+		if v := prop.Get("neve.application.name", ""); v != "Neve test application" {
+			t.Fatal("expect base value but get: ", v)
+		}
+	})
+
+	t.Run("register", func(t *testing.T) {
+		ctx := appcontext.NewDefaultApplicationContext()
+		if err := ctx.Init(conf); err != nil {
+			t.Fatal(err)
+		}
+		regs := map[string]bean.RegisterOpt{
+			"dev":     bean.SetProfiles("dev"),
+			"prod":    bean.SetProfiles("prod"),
+			"notProd": bean.SetProfiles("!prod"),
+			"notDev":  bean.SetProfiles("!dev"),
+			"any":     bean.SetProfiles("prod", "local"),
+		}
+		for name, opt := range regs {
+			if err := ctx.RegisterBeanByName(name, &aImpl{v: name}, opt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Close()
+		expect := map[string]bool{
+			"dev":     true,
+			"prod":    false,
+			"notProd": true,
+			"notDev":  false,
+			"any":     true,
+		}
+		for name, v := range expect {
+			if _, ok := ctx.GetBean(name); ok != v {
+				t.Fatalf("bean %s expect registered: %v", name, v)
+			}
+		}
+	})
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"errors"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"testing"
+)
+
+type providerSession struct {
+	id int
+}
+
+type providerLazy struct{}
+
+type providerMissing struct{}
+
+type providerHolder struct {
+	Session    func() *providerSession          `inject:""`
+	SessionErr func() (*providerSession, error) `inject:""`
+	Lazy       bean.Provider                    `inject:"providerLazy"`
+	Missing    func() (*providerMissing, error) `inject:",omiterror"`
+}
+
+type providerErrorHolder struct {
+	Session func() (*providerSession, error) `inject:""`
+}
+
+type providerCycleA struct {
+	b func() *providerCycleB
+}
+
+type providerCycleB struct {
+	a *providerCycleA
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("resolve on call", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		sessions := 0
+		ctx.RegisterBean(func() *providerSession {
+			sessions++
+			return &providerSession{id: sessions}
+		})
+		lazyCreated := 0
+		ctx.RegisterBeanByName("providerLazy", func() *providerLazy {
+			lazyCreated++
+			return &providerLazy{}
+		}, bean.SetScope(bean.Singleton), bean.SetLazy())
+		holder := &providerHolder{}
+		ctx.RegisterBean(holder)
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if sessions != 0 || lazyCreated != 0 {
+			t.Fatal("expect nothing created at injection, get ", sessions, lazyCreated)
+		}
+
+		// 多例每次调用都创建新的实例
+		s1 := holder.Session()
+		s2, err := holder.SessionErr()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s1 == s2 || s1.id != 1 || s2.id != 2 {
+			t.Fatal("expect fresh instance per call but get ", s1, s2)
+		}
+
+		l1, err := holder.Lazy.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		l2, _ := holder.Lazy.Get()
+		if l1.(*providerLazy) != l2.(*providerLazy) || lazyCreated != 1 {
+			t.Fatal("expect singleton created once but get ", lazyCreated)
+		}
+
+		if _, err := holder.Missing(); err == nil {
+			t.Fatal("expect error for missing bean")
+		}
+	})
+
+	t.Run("factory error", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		factoryErr := errors.New("session failed")
+		ctx.RegisterBean(func() (*providerSession, error) {
+			return nil, factoryErr
+		})
+		holder := &providerErrorHolder{}
+		ctx.RegisterBean(holder)
+		// 注入时不调用构造方法，启动成功
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		_, err := holder.Session()
+		var cerr *bean.CreateBeanError
+		if !errors.As(err, &cerr) || !errors.Is(err, factoryErr) {
+			t.Fatal("expect create bean error but get ", err)
+		}
+	})
+
+	t.Run("break cycle", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		ctx.RegisterBean(func(b func() *providerCycleB) *providerCycleA {
+			return &providerCycleA{b: b}
+		}, bean.SetScope(bean.Singleton))
+		ctx.RegisterBean(func(a *providerCycleA) *providerCycleB {
+			return &providerCycleB{a: a}
+		}, bean.SetScope(bean.Singleton))
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		a, err := appcontext.Get[*providerCycleA](ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := a.b()
+		if b == nil || b.a != a {
+			t.Fatal("expect b depends on a but get ", b)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/reflection"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type replaceClient struct {
+	token     string
+	set       bool
+	destroyed bool
+}
+
+func (c *replaceClient) BeanAfterSet() error {
+	c.set = true
+	return nil
+}
+
+func (c *replaceClient) BeanDestroy() error {
+	c.destroyed = true
+	return nil
+}
+
+type replaceHolder struct {
+	Client *replaceClient `inject:"client"`
+}
+
+type replaceListener struct {
+	replaced chan *appcontext.BeanReplacedEvent
+	removed  chan *appcontext.BeanRemovedEvent
+}
+
+func (l *replaceListener) OnReplaced(e *appcontext.BeanReplacedEvent) {
+	l.replaced <- e
+}
+
+func (l *replaceListener) OnRemoved(e *appcontext.BeanRemovedEvent) {
+	l.removed <- e
+}
+
+func (l *replaceListener) RegisterConsumer(registry appcontext.ApplicationEventConsumerRegistry) error {
+	err := registry.RegisterApplicationEventConsumer(l.OnReplaced)
+	if err != nil {
+		return err
+	}
+	return registry.RegisterApplicationEventConsumer(l.OnRemoved)
+}
+
+func TestReplaceBean(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	l := &replaceListener{
+		replaced: make(chan *appcontext.BeanReplacedEvent, 1),
+		removed:  make(chan *appcontext.BeanRemovedEvent, 1),
+	}
+	old := &replaceClient{token: "old"}
+	h := &replaceHolder{}
+	for name, o := range map[string]interface{}{"client": old, "holder": h, "listener": l} {
+		if err := ctx.RegisterBeanByName(name, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	if h.Client != old {
+		t.Fatal("expect old client")
+	}
+
+	n := &replaceClient{token: "new"}
+	if err := ctx.ReplaceBean("client", n); err != nil {
+		t.Fatal(err)
+	}
+	if h.Client != n || !n.set {
+		t.Fatal("expect holder re-injected with initialized new client")
+	}
+	if !old.destroyed {
+		t.Fatal("expect old client destroyed")
+	}
+	if v, _ := ctx.GetBean("client"); v != n {
+		t.Fatal("expect get new client")
+	}
+	select {
+	case e := <-l.replaced:
+		if e.GetBeanName() != "client" || e.GetOldDefinition().Interface() != old || e.GetNewDefinition().Interface() != n {
+			t.Fatal("replaced event not match")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect BeanReplacedEvent")
+	}
+
+	if err := ctx.ReplaceBean("notExist", &replaceClient{}); err == nil {
+		t.Fatal("expect not found error")
+	}
+
+	if err := ctx.RemoveBean("client"); err != nil {
+		t.Fatal(err)
+	}
+	if !n.destroyed {
+		t.Fatal("expect removed client destroyed")
+	}
+	if _, ok := ctx.GetBean("client"); ok {
+		t.Fatal("expect client removed")
+	}
+	select {
+	case e := <-l.removed:
+		if e.GetBeanName() != "client" {
+			t.Fatal("removed event not match")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect BeanRemovedEvent")
+	}
+}
+
+type replaceSvc interface {
+	Token() string
+}
+
+func (c *replaceClient) Token() string {
+	return c.token
+}
+
+type replaceSliceHolder struct {
+	Items []replaceSvc `inject:""`
+}
+
+func TestReplaceBeanSlice(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	a := &replaceClient{token: "a"}
+	h := &replaceSliceHolder{}
+	for name, o := range map[string]interface{}{"a": a, "b": &replaceClient{token: "b"}, "holder": h} {
+		if err := ctx.RegisterBeanByName(name, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	if len(h.Items) != 2 {
+		t.Fatal("expect 2 items but get ", len(h.Items))
+	}
+
+	n := &replaceClient{token: "new"}
+	if err := ctx.ReplaceBean("a", n); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Items) != 2 {
+		t.Fatal("expect 2 items but get ", len(h.Items))
+	}
+	for _, v := range h.Items {
+		if v == a {
+			t.Fatal("expect old instance not injected but get ", v.Token())
+		}
+	}
+
+	// 移除后缓存的slice中不再包含被移除的对象
+	sliceName := reflection.GetSliceName(reflect.TypeOf(h.Items))
+	if err := ctx.RemoveBean("b"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := ctx.GetBean(sliceName); ok {
+		t.Fatal("expect cached slice removed but get ", v)
+	}
+}
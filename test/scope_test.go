@@ -0,0 +1,135 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/processor"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type unitOfWork struct {
+	V         string `fig:"userdata.value"`
+	set       int32
+	destroyed int32
+}
+
+func (u *unitOfWork) BeanAfterSet() error {
+	atomic.AddInt32(&u.set, 1)
+	return nil
+}
+
+func (u *unitOfWork) BeanDestroy() error {
+	atomic.AddInt32(&u.destroyed, 1)
+	return nil
+}
+
+func TestBeanScope(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	err = ctx.Init(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	err = ctx.RegisterBean(processor.NewValueProcessor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ctx.RegisterBeanByName("uow", func() *unitOfWork {
+		return &unitOfWork{}
+	}, bean.SetScope(bean.Context))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ctx.RegisterBeanByName("broken", func() (*unitOfWork, error) {
+		return nil, errors.New("broken")
+	}, bean.SetScope(bean.Context))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ctx.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		scope := ctx.NewScope(context.Background())
+		defer scope.Close()
+		if _, ok := scope.GetBean("broken"); ok {
+			t.Fatal("expect create failed")
+		}
+		// 创建失败后作用域仍然可用
+		if _, ok := scope.GetBean("uow"); !ok {
+			t.Fatal("uow not found")
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		scope := ctx.NewScope(context.Background())
+		o1, ok := scope.GetBean("uow")
+		if !ok {
+			t.Fatal("uow not found")
+		}
+		o2, _ := scope.GetBean("uow")
+		if o1 != o2 {
+			t.Fatal("expect same instance in scope")
+		}
+		u := o1.(*unitOfWork)
+		if u.V != "this is a test" || atomic.LoadInt32(&u.set) != 1 {
+			t.Fatal("scoped bean not initialized")
+		}
+
+		other := ctx.NewScope(context.Background())
+		o3, _ := other.GetBean("uow")
+		if o3 == o1 {
+			t.Fatal("expect new instance in other scope")
+		}
+		other.Close()
+
+		if err := scope.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if atomic.LoadInt32(&u.destroyed) != 1 {
+			t.Fatal("scoped bean not destroyed")
+		}
+		if _, ok := scope.GetBean("uow"); ok {
+			t.Fatal("scope is closed")
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		scope := ctx.NewScope(c)
+		o, _ := scope.GetBean("uow")
+		cancel()
+		time.Sleep(100 * time.Millisecond)
+		if atomic.LoadInt32(&o.(*unitOfWork).destroyed) != 1 {
+			t.Fatal("scoped bean not destroyed")
+		}
+	})
+}
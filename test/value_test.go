@@ -0,0 +1,74 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/fig"
+	"github.com/xfali/neve-core/appcontext"
+	"testing"
+	"time"
+)
+
+type valueEndpoint struct {
+	Host string
+	Port int
+}
+
+type valueClient struct {
+	Timeout  time.Duration `inject:"client.timeout"`
+	Endpoint valueEndpoint `inject:""`
+}
+
+func TestValueBean(t *testing.T) {
+	conf, err := fig.LoadYamlFile("assets/application-test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appcontext.NewDefaultApplicationContext()
+	if err := ctx.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	client := &valueClient{}
+	if err := ctx.RegisterBeanByName("client.timeout", 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.RegisterBean(valueEndpoint{Host: "localhost", Port: 8080}); err != nil {
+		t.Fatal(err)
+	}
+	ctx.RegisterBean(client)
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+
+	if client.Timeout != 3*time.Second || client.Endpoint.Port != 8080 {
+		t.Fatal("expect value injected but get ", client)
+	}
+	timeout, err := appcontext.GetNamed[time.Duration](ctx, "client.timeout")
+	if err != nil || timeout != 3*time.Second {
+		t.Fatal("expect timeout but get ", timeout, err)
+	}
+
+	client.Endpoint.Host = "changed"
+	endpoint, err := appcontext.Get[valueEndpoint](ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.Host != "localhost" {
+		t.Fatal("expect bean value not changed but get ", endpoint.Host)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2022 Xiongfa Li.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"github.com/xfali/neve-core/appcontext"
+	"github.com/xfali/neve-core/bean"
+	"github.com/xfali/neve-core/injector"
+	"strings"
+	"testing"
+)
+
+type verifyService interface {
+	Serve()
+}
+
+type verifyServiceA struct{}
+
+func (s *verifyServiceA) Serve() {}
+
+type verifyServiceB struct{}
+
+func (s *verifyServiceB) Serve() {}
+
+type verifyRepo struct{}
+
+type verifyMissing struct{}
+
+type verifyHolder struct {
+	Repo     *verifyRepo     `inject:""`
+	Service  verifyService   `inject:""`
+	Missing  *verifyMissing  `inject:""`
+	Optional *verifyMissing  `inject:",omiterror"`
+	Services []verifyService `inject:""`
+	set      bool
+	funcRepo *verifyRepo
+}
+
+func (h *verifyHolder) BeanAfterSet() error {
+	h.set = true
+	return nil
+}
+
+func (h *verifyHolder) RegisterFunction(registry injector.InjectFunctionRegistry) error {
+	return registry.RegisterInjectFunction(func(repo *verifyRepo, missing *verifyMissing) {
+		h.funcRepo = repo
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		created := false
+		ctx.RegisterBean(func(repo *verifyRepo) verifyService {
+			created = true
+			return &verifyServiceA{}
+		})
+		ctx.RegisterBean(&verifyRepo{})
+		report, err := appcontext.Verify(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.OK() || report.Checked != 1 {
+			t.Fatal("expect 1 resolved dependency, get ", report)
+		}
+		if created {
+			t.Fatal("expect bean not created")
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ctx := newFactoryContext(t)
+		defer ctx.Close()
+		h := &verifyHolder{}
+		ctx.RegisterBean(h)
+		ctx.RegisterBean(&verifyRepo{})
+		ctx.RegisterBean(&verifyServiceA{})
+		ctx.RegisterBean(&verifyServiceB{})
+		ctx.RegisterBeanByName("factory", func(s *verifyMissing) *verifyServiceA {
+			return &verifyServiceA{}
+		})
+		report, err := appcontext.Verify(ctx)
+		if err == nil || report == nil || report.OK() {
+			t.Fatal("expect verify failed")
+		}
+		t.Log(report)
+		if len(report.Unresolved) != 3 || len(report.Ambiguous) != 1 || len(report.OptionalMissing) != 1 {
+			t.Fatal("expect 3 unresolved, 1 ambiguous and 1 optional missing, get ", report)
+		}
+		s := report.String()
+		for _, v := range []string{"[field:Missing]", "factory [param:0]", "#1]", "[AMBIGUOUS]", "[OPTIONAL_MISSING]"} {
+			if !strings.Contains(s, v) {
+				t.Fatalf("expect report contains %s, get %s", v, s)
+			}
+		}
+		if h.set || h.Repo != nil || h.funcRepo != nil {
+			t.Fatal("expect bean not injected or initialized")
+		}
+	})
+}
+
+type verifyDeps struct {
+	Repo    *verifyRepo    `inject:""`
+	Missing *verifyMissing `inject:""`
+}
+
+type verifyBundleHolder struct {
+	Deps verifyDeps `inject:""`
+}
+
+func TestVerifyNested(t *testing.T) {
+	ctx := newFactoryContext(t)
+	defer ctx.Close()
+	ctx.RegisterBean(&verifyRepo{})
+	ctx.RegisterBean(&verifyBundleHolder{})
+	report, err := appcontext.Verify(ctx)
+	if err == nil {
+		t.Fatal("expect verify failed")
+	}
+	if report.Checked != 2 || len(report.Unresolved) != 1 || report.Unresolved[0].Point.Field != "Deps.Missing" {
+		t.Fatal("expect nested field unresolved, get ", report)
+	}
+}
+
+type verifyConditionalHolder struct {
+	Service verifyService `inject:""`
+	set     bool
+}
+
+func (h *verifyConditionalHolder) BeanAfterSet() error {
+	h.set = true
+	return nil
+}
+
+func TestVerifyWithoutSideEffect(t *testing.T) {
+	start := func(verify bool) (appcontext.ApplicationContext, *verifyConditionalHolder) {
+		ctx := newFactoryContext(t)
+		h := &verifyConditionalHolder{}
+		ctx.RegisterBean(h)
+		ctx.RegisterBean(&verifyServiceB{}, bean.OnMissingBean(&verifyServiceA{}))
+		if verify {
+			report, err := appcontext.Verify(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 检查时满足条件，fallback被注入
+			if !report.OK() || report.Checked != 1 {
+				t.Fatal("expect resolved by fallback, get ", report)
+			}
+		}
+		// 检查之后注册的bean影响注册条件
+		ctx.RegisterBean(&verifyServiceA{})
+		if err := ctx.Start(); err != nil {
+			t.Fatal(err)
+		}
+		return ctx, h
+	}
+
+	expect, eh := start(false)
+	defer expect.Close()
+	ctx, h := start(true)
+	defer ctx.Close()
+
+	_, expectErr := appcontext.Get[*verifyServiceB](expect)
+	_, err := appcontext.Get[*verifyServiceB](ctx)
+	if err == nil || expectErr == nil {
+		t.Fatal("expect fallback removed by condition as start without verify")
+	}
+	if !h.set || !eh.set {
+		t.Fatal("expect holder initialized")
+	}
+	if _, isA := h.Service.(*verifyServiceA); !isA {
+		t.Fatal("expect verifyServiceA injected")
+	}
+	if _, isA := eh.Service.(*verifyServiceA); !isA {
+		t.Fatal("expect verifyServiceA injected")
+	}
+}